@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+)
+
+// Enum is values of named type inferred from constants of this type
+type Enum struct {
+	Values       []string
+	Names        []string
+	Descriptions []string
+}
+
+// typeEnum returns enum of named type from exported constants of this type declared in package of file.
+//
+//	Returns nil if there are no constants of type. Enums of types from GOROOT and dependencies
+//	(like time.Duration) are not inferred.
+func (p *Parser) typeEnum(name string, file File) (*Enum, error) {
+	values, err := p.structs.parseValues(file.Pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.structs.mainModule[file.Pkg.ImportPath] {
+		return nil, nil
+	}
+
+	consts := []Value{}
+	for _, v := range values {
+		// constant of type declared in the same package
+		if v.IsConst && ast.IsExported(v.Name) && v.ConstType != nil && v.ConstType.Obj().Pkg() == v.Pkg && v.ConstType.Obj().Name() == name {
+			consts = append(consts, v)
+		}
+	}
+	if len(consts) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos < consts[j].Pos
+	})
+
+	enum := &Enum{}
+	hasDescriptions := false
+	for _, v := range consts {
+		value, err := constantValue(v.Const)
+		if err != nil {
+			return nil, fmt.Errorf("constant '%s': %w", v.Name, err)
+		}
+
+		enum.Values = append(enum.Values, fmt.Sprint(value))
+		enum.Names = append(enum.Names, v.Name)
+		enum.Descriptions = append(enum.Descriptions, v.Doc)
+		hasDescriptions = hasDescriptions || v.Doc != ""
+	}
+
+	if !hasDescriptions {
+		enum.Descriptions = nil
+	}
+
+	return enum, nil
+}
+
+// setEnum sets enum values of schema, explicit values replace values inferred from constants
+func (schema *Schema) setEnum(values []string) {
+	schema.Enum = enumValues(schema.Type, values)
+	schema.EnumVarNames = nil
+	schema.EnumDescriptions = nil
+}
+
+// enumValues converts string enum values to values of given type
+func enumValues(t string, values []string) []any {
+	if values == nil {
+		return nil
+	}
+
+	enum := make([]any, len(values))
+	for i := range values {
+		enum[i] = exampleValue(t, values[i])
+	}
+
+	return enum
+}
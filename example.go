@@ -0,0 +1,832 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	zeroTime = "0001-01-01T00:00:00Z"
+)
+
+var (
+	// formatExamples placeholders of string formats
+	formatExamples = map[string]string{
+		"date-time": "2024-01-01T12:00:00Z",
+		"date":      "2024-01-01",
+		"time":      "12:00:00",
+		"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+		"email":     "user@example.com",
+		"uri":       "https://example.com",
+		"url":       "https://example.com",
+		"hostname":  "example.com",
+		"ipv4":      "192.168.0.1",
+		"ipv6":      "2001:db8::1",
+		"byte":      "ZXhhbXBsZQ==",
+		"binary":    "",
+		"password":  "********",
+	}
+	// patternExamples placeholders of patterns of validator rules
+	patternExamples = map[string]string{
+		rulePatterns["alpha"]:       "abc",
+		rulePatterns["alphanum"]:    "abc123",
+		rulePatterns["numeric"]:     "123",
+		rulePatterns["number"]:      "123",
+		rulePatterns["hexadecimal"]: "ff",
+		rulePatterns["e164"]:        "+14155552671",
+		quotedPatterns["integer"]:   "1",
+		quotedPatterns["number"]:    "1.5",
+		quotedPatterns["boolean"]:   "true",
+	}
+)
+
+// exampleField is field of struct example
+type exampleField struct {
+	Name  string
+	Value any
+}
+
+// exampleObject is struct example which keeps order of fields
+type exampleObject []exampleField
+
+func (o exampleObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(o[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o[i].Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (o exampleObject) MarshalYAML() (any, error) {
+	items := make(yaml.MapSlice, len(o))
+	for i := range o {
+		items[i] = yaml.MapItem{Key: o[i].Name, Value: o[i].Value}
+	}
+
+	return items, nil
+}
+
+// inferSchema infers schema from JSON literal
+func inferSchema(s string) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return inferValueSchema(value), nil
+}
+
+// inferValueSchema infers Schema from value decoded with json.Decoder.UseNumber
+func inferValueSchema(value any) *Schema {
+	switch v := value.(type) {
+	case string:
+		return &Schema{Type: "string"}
+
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return &Schema{Type: "number"}
+		}
+		return &Schema{Type: "integer"}
+
+	case bool:
+		return &Schema{Type: "boolean"}
+
+	case []any:
+		items := &Schema{}
+		if len(v) > 0 {
+			items = inferValueSchema(v[0])
+		}
+		return &Schema{
+			Type:  "array",
+			Items: items,
+		}
+
+	case map[string]any:
+		schema := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{},
+		}
+		for key := range v {
+			schema.Properties[key] = inferValueSchema(v[key])
+		}
+
+		return schema
+
+	default:
+		// null may be any value
+		return &Schema{}
+	}
+}
+
+// parseExample @openapiExample 200 exampleUser
+//
+//	@openapiExample request exampleCreateUser
+func (p *Parser) parseExample(s string, file File) (target string, example any, err error) {
+	parts := strings.Fields(strings.TrimPrefix(s, examplePrefix))
+	if len(parts) != 2 {
+		return "", nil, fmt.Errorf("Invalid example")
+	}
+
+	example, err = p.exampleOf(parts[1], file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if parts[0] == "request" {
+		return parts[0], example, nil
+	}
+
+	return normalizeStatus(parts[0]), example, nil
+}
+
+// setExample sets example for JSON contents of request body or response with given status
+func setExample(endpoint *Endpoint, target string, example any) error {
+	contents := endpoint.RequestBody.Content
+	if target != "request" {
+		response, ok := endpoint.Responses[target]
+		if !ok {
+			return fmt.Errorf("no %s %s for %s", trim(responsePrefix), target, trim(examplePrefix))
+		}
+		contents = response.Content
+	}
+
+	found := false
+	for contentType, content := range contents {
+		if !isJSONMediaType(contentType) {
+			continue
+		}
+		content.Example = example
+		contents[contentType] = content
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no JSON content for %s %s", trim(examplePrefix), target)
+	}
+
+	return nil
+}
+
+// structExample returns example from @openapiExample directive of struct doc
+//
+//	// @openapiExample exampleUser
+//	type User struct {...}
+func (p *Parser) structExample(st Struct, file File) (any, error) {
+	for _, l := range strings.Split(st.Doc, "\n") {
+		if strings.HasPrefix(l, examplePrefix) {
+			return p.exampleOf(trim(strings.TrimPrefix(l, examplePrefix)), file)
+		}
+	}
+
+	return nil, nil
+}
+
+// exampleOf evaluates package level variable or constant with given name
+func (p *Parser) exampleOf(name string, file File) (any, error) {
+	v, valueFile, err := p.lookupValue(name, file)
+	if err != nil {
+		return nil, err
+	}
+
+	t := ""
+	if v.Type != nil {
+		t = getType(v.Type)
+	}
+
+	var value any
+	switch {
+	case v.IsConst:
+		value, err = constantValue(v.Const)
+	case v.Value == nil:
+		value, err = p.zeroValue(t, valueFile)
+	default:
+		value, err = p.evalValue(v.Value, t, valueFile, valueFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("example '%s': %w", name, err)
+	}
+
+	return value, nil
+}
+
+// lookupValue finds package level constant or variable by name, name may contain package
+func (p *Parser) lookupValue(name string, file File) (Value, File, error) {
+	pkgName, name := splitName(name)
+	pkg := file.Pkg
+	if pkgName != "" {
+		if !ast.IsExported(name) {
+			return Value{}, File{}, fmt.Errorf("'%s.%s' is not exported", pkgName, name)
+		}
+
+		var err error
+		pkg, err = file.ParseImportPackage(pkgName)
+		if err != nil {
+			return Value{}, File{}, err
+		}
+	}
+
+	values, err := p.structs.parseValues(pkg)
+	if err != nil {
+		return Value{}, File{}, err
+	}
+
+	v, ok := values[name]
+	if !ok {
+		return Value{}, File{}, fmt.Errorf("constant or variable with name '%s' was not found in package '%s' with import path '%s'", name, pkg.FSPath, pkg.ImportPath)
+	}
+
+	valueFile := NewFile(v.File, pkg.FSPath, pkg.ImportPath)
+	valueFile.Info = v.Info
+
+	return v, valueFile, nil
+}
+
+// evalValue evaluates expression to value which can be marshaled to JSON
+//
+//	Constant expressions are evaluated by type checker, composite literals and variables are walked.
+//	t - expected type of value declared in typeFile, may be empty for untyped constants
+//	file - file where expression is declared
+func (p *Parser) evalValue(e ast.Expr, t string, file, typeFile File) (any, error) {
+	if tv, ok := file.typeAndValue(e); ok && tv.Value != nil {
+		return constantValue(tv.Value)
+	}
+
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return p.evalValue(e.X, t, file, typeFile)
+
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return p.evalValue(e.X, strings.TrimPrefix(t, "*"), file, typeFile)
+		}
+
+	case *ast.CompositeLit:
+		return p.evalComposite(e, t, file, typeFile)
+
+	case *ast.CallExpr:
+		// conversion of not constant value
+		if tv, ok := file.typeAndValue(e.Fun); ok && tv.IsType() && len(e.Args) == 1 {
+			return p.evalValue(e.Args[0], getType(e.Fun), file, file)
+		}
+
+	case *ast.Ident, *ast.SelectorExpr:
+		if tv, ok := file.typeAndValue(e); ok && tv.IsNil() {
+			return nil, nil
+		}
+
+		v, valueFile, err := p.lookupValue(getType(e), file)
+		if err != nil {
+			return nil, err
+		}
+		if v.IsConst {
+			return constantValue(v.Const)
+		}
+		if v.Type != nil {
+			t = getType(v.Type)
+			typeFile = valueFile
+		}
+		if v.Value == nil {
+			return p.zeroValue(t, typeFile)
+		}
+
+		return p.evalValue(v.Value, t, valueFile, typeFile)
+	}
+
+	return nil, fmt.Errorf("unsupported expression")
+}
+
+// evalComposite evaluates composite literal of struct, slice or map
+func (p *Parser) evalComposite(lit *ast.CompositeLit, t string, file, typeFile File) (any, error) {
+	// type of anonymous struct is known from field only
+	if _, ok := lit.Type.(*ast.StructType); !ok && lit.Type != nil {
+		t = getType(lit.Type)
+		typeFile = file
+	}
+	if t == "" {
+		return nil, fmt.Errorf("unknown type of composite literal")
+	}
+
+	parsedType, err := p.parseType(t, typeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsedType.Kind {
+	case arrayType:
+		elemType := strings.TrimPrefix(parsedType.Name, "[]")
+		values := make([]any, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+
+			value, err := p.evalValue(elt, elemType, file, parsedType.File)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+
+	case mapType:
+		_, valueType, _ := splitMapType(parsedType.Name)
+		values := map[string]any{}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return nil, fmt.Errorf("invalid map element")
+			}
+
+			key, err := p.evalValue(kv.Key, "", file, parsedType.File)
+			if err != nil {
+				return nil, err
+			}
+			value, err := p.evalValue(kv.Value, valueType, file, parsedType.File)
+			if err != nil {
+				return nil, err
+			}
+			values[fmt.Sprint(key)] = value
+		}
+		return values, nil
+
+	case structType:
+		return p.evalStruct(lit, parsedType, file)
+
+	case timeType:
+		if len(lit.Elts) == 0 {
+			return zeroTime, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported composite literal of type '%s'", t)
+}
+
+// evalStruct evaluates struct composite literal, fields are named and omitted like encoding/json does
+func (p *Parser) evalStruct(lit *ast.CompositeLit, t *ParsedType, file File) (any, error) {
+	st, err := p.lookupStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]ast.Expr{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("unkeyed fields are not supported in composite literal of '%s'", t.Name)
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("invalid field in composite literal of '%s'", t.Name)
+		}
+		values[key.Name] = kv.Value
+	}
+
+	fields, err := p.structFields(t, []string{"json"}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// values of embedded structs by embedded field name
+	embedded := map[string]any{}
+	object := exampleObject{}
+	for _, field := range fields {
+		if field.IsSystem {
+			continue
+		}
+
+		// promoted field is taken from value of embedded struct
+		if len(field.path) > 0 {
+			name := field.path[0]
+			if _, ok := embedded[name]; !ok {
+				embedded[name], err = p.evalEmbedded(st, name, values[name], file, t.File)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s': %w", name, err)
+				}
+			}
+
+			if value, ok := embedded[name].(exampleObject); ok {
+				for i := range value {
+					if value[i].Name == field.Key {
+						object = append(object, value[i])
+					}
+				}
+			}
+			continue
+		}
+
+		var value any
+		if e, ok := values[field.Name]; ok {
+			value, err = p.evalValue(e, field.Type, file, field.File)
+		} else {
+			value, err = p.zeroValue(field.Type, field.File)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+
+		if field.hasOption("omitempty") && isEmptyValue(value) {
+			continue
+		}
+		if field.hasOption("omitzero") {
+			zero, err := p.zeroValue(field.Type, field.File)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+			}
+			if isZeroValue(value, zero) {
+				continue
+			}
+		}
+		if field.hasOption("string") {
+			value = quoteValue(value)
+		}
+
+		object = append(object, exampleField{
+			Name:  field.Key,
+			Value: value,
+		})
+	}
+
+	return object, nil
+}
+
+// evalEmbedded evaluates value of embedded struct field, nil pointer has no fields
+func (p *Parser) evalEmbedded(st Struct, name string, e ast.Expr, file, typeFile File) (any, error) {
+	for _, field := range st.Fields {
+		if !field.IsEmbedded || field.Name != name {
+			continue
+		}
+
+		if e == nil {
+			return p.zeroValue(field.Type, typeFile)
+		}
+
+		return p.evalValue(e, field.Type, file, typeFile)
+	}
+
+	return nil, nil
+}
+
+// zeroValue returns JSON value of zero value of given type
+func (p *Parser) zeroValue(t string, file File) (any, error) {
+	if t == "" || strings.HasPrefix(t, "*") {
+		return nil, nil
+	}
+
+	parsedType, err := p.parseType(t, file)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsedType.Kind {
+	case baseType:
+		switch typesMap[parsedType.Name] {
+		case "integer", "number":
+			return 0, nil
+		case "boolean":
+			return false, nil
+		case "string":
+			if parsedType.Name == "[]byte" {
+				return nil, nil
+			}
+			return "", nil
+		}
+
+	case timeType:
+		return zeroTime, nil
+
+	case structType:
+		return p.evalStruct(&ast.CompositeLit{}, parsedType, file)
+	}
+
+	return nil, nil
+}
+
+// constantValue converts constant to value which can be marshaled to JSON
+func constantValue(c constant.Value) (any, error) {
+	switch c.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(c), nil
+
+	case constant.String:
+		return constant.StringVal(c), nil
+
+	case constant.Int:
+		if i, ok := constant.Int64Val(c); ok {
+			return i, nil
+		}
+		if i, ok := constant.Uint64Val(c); ok {
+			return i, nil
+		}
+		return nil, fmt.Errorf("constant %s overflows", c)
+
+	case constant.Float:
+		f, _ := constant.Float64Val(c)
+		return f, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported constant %s", c)
+	}
+}
+
+// isEmptyValue reports whether value is omitted by omitempty
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case int64:
+		return v == 0
+	case uint64:
+		return v == 0
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// isZeroValue reports whether value is equal to zero value of its type, empty slices and maps are not zero
+func isZeroValue(value, zero any) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	zeroData, err := json.Marshal(zero)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(data, zeroData)
+}
+
+// quoteValue encodes number, boolean or string as JSON string like string option of json tag does
+func quoteValue(value any) any {
+	switch value.(type) {
+	case bool, string, int, int64, uint64, float64:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		return string(data)
+	default:
+		return value
+	}
+}
+
+// generateExamples sets examples synthesized from schemas for JSON contents of requests and responses without example
+func generateExamples(doc *Doc) {
+	g := exampleGenerator{
+		schemas: doc.Components.Schemas,
+		refs:    map[string]bool{},
+	}
+
+	for path := range doc.Paths {
+		for method := range doc.Paths[path] {
+			endpoint := doc.Paths[path][method]
+			g.contents(endpoint.RequestBody.Content)
+			for status := range endpoint.Responses {
+				g.contents(endpoint.Responses[status].Content)
+			}
+		}
+	}
+}
+
+// exampleGenerator synthesizes examples from schemas
+type exampleGenerator struct {
+	schemas map[string]*Schema
+	// refs which are generated now, used to stop recursion of self-referencing types
+	refs map[string]bool
+}
+
+func (g *exampleGenerator) contents(contents map[string]Content) {
+	for contentType, content := range contents {
+		if content.Example != nil || content.Schema == nil || !isJSONMediaType(contentType) {
+			continue
+		}
+
+		value, ok := g.schema(content.Schema)
+		if !ok {
+			continue
+		}
+
+		content.Example = value
+		contents[contentType] = content
+	}
+}
+
+// stringExample returns placeholder of string matching known pattern and length limits of schema.
+//
+//	Arbitrary patterns are not handled.
+func stringExample(schema *Schema) string {
+	example, ok := patternExamples[schema.Pattern]
+	if !ok {
+		example = "string"
+	}
+
+	if schema.MinLength != nil && len(example) < *schema.MinLength {
+		example += strings.Repeat(example[len(example)-1:], *schema.MinLength-len(example))
+	}
+	if schema.MaxLength != nil && len(example) > *schema.MaxLength {
+		example = example[:max(*schema.MaxLength, 0)]
+	}
+
+	return example
+}
+
+// numberExample returns zero moved into range of minimum and maximum of schema
+func numberExample(schema *Schema) float64 {
+	integer := schema.Type == "integer"
+	value := float64(0)
+	if schema.Minimum != nil {
+		minimum := float64(*schema.Minimum)
+		if value < minimum || schema.ExclusiveMinimum && value <= minimum {
+			switch {
+			case integer && schema.ExclusiveMinimum:
+				value = math.Floor(minimum) + 1
+			case integer:
+				value = math.Ceil(minimum)
+			case schema.ExclusiveMinimum:
+				value = minimum + 0.5
+			default:
+				value = minimum
+			}
+		}
+	}
+	if schema.Maximum != nil {
+		maximum := float64(*schema.Maximum)
+		if value > maximum || schema.ExclusiveMaximum && value >= maximum {
+			switch {
+			case integer && schema.ExclusiveMaximum:
+				value = math.Ceil(maximum) - 1
+			case integer:
+				value = math.Floor(maximum)
+			case schema.ExclusiveMaximum:
+				value = maximum - 0.5
+			default:
+				value = maximum
+			}
+		}
+	}
+
+	return value
+}
+
+// schema returns example of schema and false if example can't be generated because of recursion
+func (g *exampleGenerator) schema(schema *Schema) (any, bool) {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		ref, ok := g.schemas[name]
+		if !ok || g.refs[name] {
+			return nil, false
+		}
+
+		g.refs[name] = true
+		defer delete(g.refs, name)
+
+		return g.schema(ref)
+	}
+
+	// reference wrapped by wrapRef is omitted when it can't be generated
+	if len(schema.AllOf) == 1 {
+		return g.schema(schema.AllOf[0])
+	}
+
+	if len(schema.AllOf) > 0 {
+		object := map[string]any{}
+		for i := range schema.AllOf {
+			value, ok := g.schema(schema.AllOf[i])
+			if !ok {
+				continue
+			}
+			if v, ok := value.(map[string]any); ok {
+				for key := range v {
+					object[key] = v[key]
+				}
+			}
+		}
+		return object, true
+	}
+
+	if schema.Example != nil {
+		return schema.Example, true
+	}
+	if schema.Default != nil {
+		return schema.Default, true
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0], true
+	}
+
+	switch schema.Type {
+	case "string":
+		if example, ok := formatExamples[schema.Format]; ok {
+			return example, true
+		}
+		return stringExample(schema), true
+
+	case "integer":
+		return int(numberExample(schema)), true
+
+	case "number":
+		return numberExample(schema), true
+
+	case "boolean":
+		return true, true
+
+	case "array":
+		values := []any{}
+		if schema.Items != nil {
+			if value, ok := g.schema(schema.Items); ok {
+				values = append(values, value)
+			}
+		}
+		return values, true
+	}
+
+	if schema.Type == "object" || schema.Properties != nil {
+		object := map[string]any{}
+		for name := range schema.Properties {
+			if value, ok := g.schema(schema.Properties[name]); ok {
+				object[name] = value
+			}
+		}
+		if schema.AdditionalProperties != nil {
+			if value, ok := g.schema(schema.AdditionalProperties); ok && value != nil {
+				object["key"] = value
+			}
+		}
+		return object, true
+	}
+
+	// any value
+	return nil, true
+}
+
+// exampleValue converts string value of example, default or enum to value of given type
+func exampleValue(t, s string) any {
+	switch t {
+	case "string":
+		return s
+
+	case "integer":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+
+	default:
+		var value any
+		if err := json.Unmarshal([]byte(s), &value); err == nil {
+			return value
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Field is struct field resolved with file of its struct
+type Field struct {
+	StructField
+	// Key name of field from tag or field name
+	Key  string
+	File File
+
+	// path names of embedded fields which promote field
+	path   []string
+	depth  int
+	tagged bool
+	// promoted through embedded pointer, field is absent when pointer is nil
+	viaPointer bool
+	// options of tag which names field, like omitempty and string of json tag
+	options []string
+}
+
+// hasOption reports whether tag which names field has option
+func (f Field) hasOption(option string) bool {
+	return strIn(option, f.options)
+}
+
+// structFields returns fields of struct with fields of embedded structs promoted like encoding/json does.
+//
+//	tagNames - tags which name fields, first not empty tag is used
+//	promote - promote fields of embedded structs, otherwise embedded structs are returned as fields
+func (p *Parser) structFields(t *ParsedType, tagNames []string, promote bool) ([]Field, error) {
+	st, err := p.lookupStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []Field{}
+	err = p.collectFields(st, t.File, tagNames, promote, nil, false, map[string]bool{}, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string][]int{}
+	for i := range fields {
+		if !fields[i].IsSystem {
+			names[fields[i].Key] = append(names[fields[i].Key], i)
+		}
+	}
+
+	resp := []Field{}
+	for i := range fields {
+		if fields[i].IsSystem || dominantField(fields, names[fields[i].Key]) == i {
+			resp = append(resp, fields[i])
+		}
+	}
+
+	return resp, nil
+}
+
+// inlineEmbedded replaces embedded pointers and embedded structs having shadowed or ambiguous fields by their promoted fields.
+//
+//	allOf with reference to such struct would describe fields which encoding/json doesn't write,
+//	fields of nil embedded pointer are omitted, but referenced schema would require them.
+func (p *Parser) inlineEmbedded(t *ParsedType, fields []Field, tagNames []string) ([]Field, error) {
+	promoted, err := p.structFields(t, tagNames, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := []Field{}
+	for _, field := range fields {
+		if !field.IsEmbedded || field.tagged {
+			resp = append(resp, field)
+			continue
+		}
+
+		parsedType, err := p.parseType(field.Type, field.File)
+		if err != nil {
+			return nil, fmt.Errorf("embedded field '%s': %w", field.Name, err)
+		}
+		own, err := p.structFields(parsedType, tagNames, true)
+		if err != nil {
+			return nil, err
+		}
+
+		inherited := []Field{}
+		for _, f := range promoted {
+			if len(f.path) > field.depth && f.path[field.depth] == field.Name {
+				inherited = append(inherited, f)
+			}
+		}
+
+		ownCount := 0
+		for _, f := range own {
+			if !f.IsSystem {
+				ownCount++
+			}
+		}
+		if len(inherited) == ownCount && !field.IsPointer {
+			resp = append(resp, field)
+		} else {
+			resp = append(resp, inherited...)
+		}
+	}
+
+	return resp, nil
+}
+
+func (p *Parser) collectFields(st Struct, file File, tagNames []string, promote bool, path []string, viaPointer bool, visited map[string]bool, fields *[]Field) error {
+	// embedded struct may embed itself through pointer
+	key := st.Pkg + "." + st.Name
+	if visited[key] {
+		return nil
+	}
+	visited[key] = true
+	defer delete(visited, key)
+
+	for _, field := range st.Fields {
+		if field.IsSystem {
+			if len(path) == 0 {
+				*fields = append(*fields, Field{StructField: field, File: file})
+			}
+			continue
+		}
+
+		name, options := "", []string(nil)
+		for _, tagName := range tagNames {
+			if name, options = parseTag(field.Tag, tagName); name != "" {
+				break
+			}
+		}
+		// "-," names field "-"
+		if name == "-" && len(options) == 0 {
+			continue
+		}
+		if !isValidTagName(name) {
+			name = ""
+		}
+
+		embeddedStruct := false
+		if field.IsEmbedded && (name == "" || !field.IsExported) {
+			parsedType, err := p.parseType(field.Type, file)
+			if err != nil {
+				return fmt.Errorf("embedded field '%s': %w", field.Name, err)
+			}
+			embeddedStruct = parsedType.Kind == structType
+
+			if embeddedStruct && name == "" {
+				if !promote {
+					*fields = append(*fields, Field{StructField: field, Key: field.Name, File: file, path: path, depth: len(path), viaPointer: viaPointer, options: options})
+					continue
+				}
+
+				embedded, err := p.lookupStruct(parsedType)
+				if err != nil {
+					return err
+				}
+
+				embeddedPath := append(append([]string{}, path...), field.Name)
+				err = p.collectFields(embedded, parsedType.File, tagNames, promote, embeddedPath, viaPointer || field.IsPointer, visited, fields)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// unexported embedded struct named by tag is field like encoding/json does
+		if !field.IsExported && !embeddedStruct {
+			continue
+		}
+
+		f := Field{
+			StructField: field,
+			Key:         name,
+			File:        file,
+			path:        path,
+			depth:       len(path),
+			tagged:      name != "",
+			viaPointer:  viaPointer,
+			options:     options,
+		}
+		if f.Key == "" {
+			f.Key = field.Name
+		}
+		*fields = append(*fields, f)
+	}
+
+	return nil
+}
+
+// dominantField returns index of field which is used for name or -1 if fields with name are ambiguous.
+//
+//	Shallowest field wins, tagged field wins among fields of the same depth.
+//	Names are compared exactly: json.Marshal writes names which differ only by case as different keys,
+//	case-insensitive matching of json.Unmarshal doesn't change schema.
+func dominantField(fields []Field, indexes []int) int {
+	dominant := []int{}
+	for _, i := range indexes {
+		if len(dominant) == 0 || fields[i].depth < fields[dominant[0]].depth {
+			dominant = []int{i}
+		} else if fields[i].depth == fields[dominant[0]].depth {
+			dominant = append(dominant, i)
+		}
+	}
+
+	if len(dominant) == 1 {
+		return dominant[0]
+	}
+
+	tagged := []int{}
+	for _, i := range dominant {
+		if fields[i].tagged {
+			tagged = append(tagged, i)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0]
+	}
+
+	return -1
+}
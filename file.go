@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +21,10 @@ type Package struct {
 type File struct {
 	ParsedFile *ast.File
 	Pkg        Package
+	// TypeArgs type arguments by type parameter names for fields of generic type
+	TypeArgs map[string]*ParsedType
+	// Info types of expressions of ParsedFile, set for files of type checked packages
+	Info *types.Info
 }
 
 func NewFile(file *ast.File, fsPath, importPath string) File {
@@ -32,6 +37,16 @@ func NewFile(file *ast.File, fsPath, importPath string) File {
 	}
 }
 
+// typeAndValue returns type and constant value of expression recorded by type checker
+func (f File) typeAndValue(e ast.Expr) (types.TypeAndValue, bool) {
+	if f.Info == nil {
+		return types.TypeAndValue{}, false
+	}
+
+	tv, ok := f.Info.Types[e]
+	return tv, ok
+}
+
 // ParseImport search import in current File and returns parsed File with import's sources
 func (f *File) ParseImport(pkgName, typeName string) (File, error) {
 	importedPkgPath, found := f.getImportPathForPkg(pkgName, f.ParsedFile)
@@ -52,6 +67,24 @@ func (f *File) ParseImport(pkgName, typeName string) (File, error) {
 	return NewFile(fileWithImportedType, importedPkgFSPath, importedPkgPath), nil
 }
 
+// ParseImportPackage search import in current File and returns imported Package
+func (f *File) ParseImportPackage(pkgName string) (Package, error) {
+	importedPkgPath, found := f.getImportPathForPkg(pkgName, f.ParsedFile)
+	if !found {
+		return Package{}, fmt.Errorf("not found import path for package: %s", pkgName)
+	}
+
+	importedPkgFSPath, err := f.resolvePkgFSPath(importedPkgPath, f.Pkg.FSPath)
+	if err != nil {
+		return Package{}, err
+	}
+
+	return Package{
+		FSPath:     importedPkgFSPath,
+		ImportPath: importedPkgPath,
+	}, nil
+}
+
 func (f *File) getImportPathName(fileImport *ast.ImportSpec) string {
 	if fileImport.Name != nil {
 		return fileImport.Name.Name
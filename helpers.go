@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 func getStr(ss []string, i int) string {
@@ -33,12 +34,38 @@ func getTag(s, tag string) string {
 	return strings.Split(t.Get(tag), ",")[0]
 }
 
+// parseTag returns name and options of tag, for example "id" and ["omitempty", "string"] of json:"id,omitempty,string"
+func parseTag(s, tag string) (string, []string) {
+	t := reflect.StructTag(strings.Trim(s, "`"))
+	parts := strings.Split(t.Get(tag), ",")
+	return parts[0], parts[1:]
+}
+
+// isValidTagName reports whether name of tag is valid name of JSON field, invalid names are ignored by encoding/json
+func isValidTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+
+	return true
+}
+
 type Tags struct {
 	Openapi     map[string]string
 	Description string
 	Enum        string
 	Example     string
 	Extensions  map[string]string
+	Encoding    map[string]string
+	Validate    string
+	Binding     string
 }
 
 func getParamsFromTag(s string) (Tags, error) {
@@ -60,12 +87,20 @@ func getParamsFromTag(s string) (Tags, error) {
 		return Tags{}, err
 	}
 
+	encoding, err := parseParams(t.Get("openapiEncoding"))
+	if err != nil {
+		return Tags{}, err
+	}
+
 	return Tags{
 		Openapi:     params,
 		Description: description,
 		Enum:        enum,
 		Example:     example,
 		Extensions:  extensions,
+		Encoding:    encoding,
+		Validate:    t.Get("validate"),
+		Binding:     t.Get("binding"),
 	}, nil
 }
 
@@ -108,14 +143,164 @@ func parseParams(str string) (map[string]string, error) {
 }
 
 func parseParam(str string) (string, string, error) {
-	ss := strings.Split(str, "=")
-	key := trim(ss[0])
-	value := strings.ReplaceAll(trim(getStr(ss, 1)), `'`, `"`)
+	key, value, _ := strings.Cut(str, "=")
+	key = trim(key)
+	value = strings.ReplaceAll(trim(value), `'`, `"`)
 
 	return key, value, nil
 
 }
 
+// cutQuoted cuts leading double quoted string from s
+func cutQuoted(s string) (quoted string, rest string, ok bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", s, true
+	}
+
+	end := strings.Index(s[1:], `"`)
+	if end < 0 {
+		return "", s, false
+	}
+
+	return s[1 : end+1], trim(s[end+2:]), true
+}
+
+// cutMediaTypes cuts leading comma separated media types from s, media types may be separated by comma and spaces
+func cutMediaTypes(s string) (mediaTypes []string, rest string) {
+	list, rest, _ := strings.Cut(trim(s), " ")
+	rest = trim(rest)
+	for rest != "" && (strings.HasSuffix(list, ",") || strings.HasPrefix(rest, ",")) {
+		var next string
+		next, rest, _ = strings.Cut(rest, " ")
+		list += next
+		rest = trim(rest)
+	}
+
+	for _, mediaType := range strings.Split(list, ",") {
+		mediaTypes = append(mediaTypes, trim(mediaType))
+	}
+
+	return mediaTypes, rest
+}
+
+// splitFields splits string by spaces which are not enclosed in brackets
+func splitFields(s string) []string {
+	fields := []string{}
+	temp := []rune{}
+	opened := 0
+	for _, r := range s {
+		switch r {
+		case '[', '{', '(':
+			opened++
+		case ']', '}', ')':
+			opened--
+		}
+
+		if r != ' ' && r != '\t' || opened > 0 {
+			temp = append(temp, r)
+			continue
+		}
+
+		if len(temp) > 0 {
+			fields = append(fields, string(temp))
+		}
+		temp = []rune{}
+	}
+
+	if len(temp) > 0 {
+		fields = append(fields, string(temp))
+	}
+
+	return fields
+}
+
+// getBool returns value of boolean param, param without value is true
+func getBool(params map[string]string, key string) (value bool, ok bool) {
+	v, ok := params[key]
+	if !ok {
+		return false, false
+	}
+
+	return v == "" || v == "true", true
+}
+
+// checkBools returns error for boolean params with value other than true or false
+func checkBools(params map[string]string, keys ...string) error {
+	for _, key := range keys {
+		if v, ok := params[key]; ok && v != "" && v != "true" && v != "false" {
+			return fmt.Errorf("Invalid param '%s'", key)
+		}
+	}
+
+	return nil
+}
+
+// splitMapType splits map type to key and value types, for example map[string][]int -> string, []int
+func splitMapType(t string) (string, string, bool) {
+	if !strings.HasPrefix(t, "map[") {
+		return "", "", false
+	}
+
+	opened := 1
+	for i := len("map["); i < len(t); i++ {
+		switch t[i] {
+		case '[':
+			opened++
+		case ']':
+			opened--
+		}
+		if opened == 0 {
+			return t[len("map["):i], t[i+1:], i+1 < len(t)
+		}
+	}
+
+	return "", "", false
+}
+
+// splitGenericType splits instantiation of generic type to type name and type arguments, for example Page[User] -> Page, [User]
+func splitGenericType(t string) (string, []string, bool) {
+	i := strings.Index(t, "[")
+	if i <= 0 || strings.HasPrefix(t, "map[") || !strings.HasSuffix(t, "]") {
+		return "", nil, false
+	}
+
+	args := []string{}
+	opened := 0
+	start := i + 1
+	for j := start; j < len(t)-1; j++ {
+		switch t[j] {
+		case '[':
+			opened++
+		case ']':
+			opened--
+		case ',':
+			if opened == 0 {
+				args = append(args, trim(t[start:j]))
+				start = j + 1
+			}
+		}
+	}
+	args = append(args, trim(t[start:len(t)-1]))
+
+	return t[:i], args, true
+}
+
+// docDescription returns description from doc comment without @openapi directives
+func docDescription(doc string) string {
+	lines := []string{}
+	for _, l := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(l, "@openapi") {
+			lines = append(lines, l)
+		}
+	}
+
+	return trim(strings.Join(lines, "\n"))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func strIn(s string, ss []string) bool {
 	for i := range ss {
 		if ss[i] == s {
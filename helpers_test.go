@@ -21,6 +21,16 @@ func TestStrIn(t *testing.T) {
 	require.False(t, strIn("4", []string{"1", "2", "3"}))
 }
 
+func TestCutMediaTypes(t *testing.T) {
+	mediaTypes, rest := cutMediaTypes("application/json, application/xml ,text/plain User")
+	require.Equal(t, []string{"application/json", "application/xml", "text/plain"}, mediaTypes)
+	require.Equal(t, "User", rest)
+
+	mediaTypes, rest = cutMediaTypes("application/json {\"id\": int}")
+	require.Equal(t, []string{"application/json"}, mediaTypes)
+	require.Equal(t, `{"id": int}`, rest)
+}
+
 func TestGetParamsFromTag(t *testing.T) {
 	tags, err := getParamsFromTag(`openapiDesc:"foo" openapiExample:"22" openapiEnum:"1,2,3" openapi:"required" openapiExt:"x-test=test"`)
 	require.Nil(t, err)
@@ -76,3 +86,9 @@ func TestAddPkg(t *testing.T) {
 	require.Equal(t, "test.bar", addPkg("test", "test.bar"))
 	require.Equal(t, "test.bar", addPkg("foo", "test.bar"))
 }
+
+func TestDocDescription(t *testing.T) {
+	require.Equal(t, "", docDescription(""))
+	require.Equal(t, "User of service", docDescription("User of service\n"))
+	require.Equal(t, "User of service\nwith roles", docDescription("User of service\n@openapiExample exampleUser\nwith roles\n"))
+}
@@ -28,6 +28,7 @@ func main() {
 		dir          string
 		skipDirs     string
 		indent       int
+		options      Options
 	)
 
 	flag.StringVar(&version, "v", "1.0.0", "Docs version")
@@ -37,6 +38,12 @@ func main() {
 	flag.StringVar(&dir, "path", "", "Path with go files")
 	flag.StringVar(&skipDirs, "skip", "", "paths to skipping")
 	flag.IntVar(&indent, "indent", 2, "Yaml indentation")
+	flag.BoolVar(&options.ExampleOnly, "example-only", false, "Keep JSON literals as examples without inferred schema")
+	flag.BoolVar(&options.GenerateExamples, "examples", false, "Generate examples from schemas for requests and responses without example")
+	flag.BoolVar(&options.EmbeddedAllOf, "embedded-allof", false, "Reference embedded structs by allOf instead of promoting their fields")
+	flag.BoolVar(&options.ValidateTag, "validate-tag", false, "Map validator rules of validate tag to schema constraints")
+	flag.BoolVar(&options.BindingTag, "binding-tag", false, "Map validator rules of binding tag to schema constraints")
+	flag.BoolVar(&options.InferRequired, "infer-required", false, "Mark JSON fields which are neither pointers nor omitempty as required")
 	flag.BoolVar(&debug, "debug", false, "enable debug")
 	flag.Parse()
 
@@ -72,7 +79,7 @@ func main() {
 			return
 		}
 
-		p := NewParser(&doc, newStructsParser())
+		p := NewParser(&doc, newStructsParser()).WithOptions(options)
 		for _, pkg := range pkgs {
 			for filePath, f := range pkg.Files {
 				for _, c := range f.Comments {
@@ -85,6 +92,14 @@ func main() {
 		}
 	}
 
+	if options.GenerateExamples {
+		generateExamples(&doc)
+	}
+
+	for _, err := range validateDoc(&doc) {
+		errors = append(errors, err.Error())
+	}
+
 	if len(errors) > 0 {
 		for i := range errors {
 			log.Println(errors[i])
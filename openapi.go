@@ -1,70 +1,124 @@
 package main
 
-type Property struct {
-	Type                 string              `yaml:"type,omitempty"`
-	Description          string              `yaml:"description,omitempty"`
-	Format               string              `yaml:"format,omitempty"`
-	Minimum              int                 `yaml:"minimum,omitempty"`
-	Maximum              int                 `yaml:"maximum,omitempty"`
-	Enum                 []string            `yaml:"enum,omitempty"`
-	Default              string              `yaml:"default,omitempty"`
-	Example              string              `yaml:"example,omitempty"`
-	Style                string              `yaml:"style,omitempty"`
-	Explode              bool                `yaml:"explode,omitempty"`
-	Properties           map[string]Property `yaml:"properties,omitempty"`
-	AdditionalProperties *map[string]string  `yaml:"additionalProperties,omitempty"`
-	Items                *Schema             `yaml:"items,omitempty"`
-	Ref                  string              `yaml:"$ref,omitempty"`
-	Extensions           map[string]string   `yaml:",inline"`
-}
+import "math"
 
+// Schema is OpenAPI 3.0 schema object of types, properties, items and map values
 type Schema struct {
 	// service field for deduplication
-	importPath  string              `yaml:"-"`
-	Type        string              `yaml:"type,omitempty"`
-	Format      string              `yaml:"format,omitempty"`
-	Ref         string              `yaml:"$ref,omitempty"`
-	Properties  map[string]Property `yaml:"properties,omitempty"`
-	Required    []string            `yaml:"required,omitempty"`
-	Items       *Schema             `yaml:"items,omitempty"`
-	Description string              `yaml:"description,omitempty"`
+	importPath  string `yaml:"-"`
+	Ref         string `yaml:"$ref,omitempty"`
+	Type        string `yaml:"type,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Nullable    bool   `yaml:"nullable,omitempty"`
+	ReadOnly    bool   `yaml:"readOnly,omitempty"`
+	WriteOnly   bool   `yaml:"writeOnly,omitempty"`
+	Deprecated  bool   `yaml:"deprecated,omitempty"`
+	Default     any    `yaml:"default,omitempty"`
+	Example     any    `yaml:"example,omitempty"`
+
+	Enum             []any    `yaml:"enum,omitempty"`
+	EnumVarNames     []string `yaml:"x-enum-varnames,omitempty"`
+	EnumDescriptions []string `yaml:"x-enum-descriptions,omitempty"`
+
+	MultipleOf       *Number `yaml:"multipleOf,omitempty"`
+	Minimum          *Number `yaml:"minimum,omitempty"`
+	Maximum          *Number `yaml:"maximum,omitempty"`
+	ExclusiveMinimum bool    `yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool    `yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *int    `yaml:"minLength,omitempty"`
+	MaxLength        *int    `yaml:"maxLength,omitempty"`
+	Pattern          string  `yaml:"pattern,omitempty"`
+
+	Items       *Schema `yaml:"items,omitempty"`
+	MinItems    *int    `yaml:"minItems,omitempty"`
+	MaxItems    *int    `yaml:"maxItems,omitempty"`
+	UniqueItems bool    `yaml:"uniqueItems,omitempty"`
+
+	Properties map[string]*Schema `yaml:"properties,omitempty"`
+	// AdditionalProperties schema of map values
+	AdditionalProperties *Schema  `yaml:"additionalProperties,omitempty"`
+	Required             []string `yaml:"required,omitempty"`
+	MinProperties        *int     `yaml:"minProperties,omitempty"`
+	MaxProperties        *int     `yaml:"maxProperties,omitempty"`
+
+	AllOf []*Schema `yaml:"allOf,omitempty"`
+	OneOf []*Schema `yaml:"oneOf,omitempty"`
+	AnyOf []*Schema `yaml:"anyOf,omitempty"`
+	Not   *Schema   `yaml:"not,omitempty"`
+
+	Extensions map[string]string `yaml:",inline"`
 }
 
 type Content struct {
-	Schema  *Schema `yaml:"schema,omitempty"`
-	Example string  `yaml:"example,omitempty"`
+	Schema   *Schema             `yaml:"schema,omitempty"`
+	Example  any                 `yaml:"example,omitempty"`
+	Encoding map[string]Encoding `yaml:"encoding,omitempty"`
+	// ItemSchema schema of every item of streaming media type (itemSchema of OpenAPI 3.2)
+	ItemSchema *Schema  `yaml:"x-item-schema,omitempty"`
+	Events     []string `yaml:"x-events,omitempty"`
+}
+
+type Encoding struct {
+	ContentType   string            `yaml:"contentType,omitempty"`
+	Headers       map[string]Header `yaml:"headers,omitempty"`
+	Style         string            `yaml:"style,omitempty"`
+	Explode       *bool             `yaml:"explode,omitempty"`
+	AllowReserved bool              `yaml:"allowReserved,omitempty"`
 }
 
 type Parameter struct {
-	Name        string    `yaml:"name,omitempty"`
-	In          string    `yaml:"in,omitempty"`
-	Required    bool      `yaml:"required"`
-	Description string    `yaml:"description,omitempty"`
-	Schema      *Property `yaml:"schema,omitempty"`
+	Name            string             `yaml:"name,omitempty"`
+	In              string             `yaml:"in,omitempty"`
+	Required        bool               `yaml:"required"`
+	Description     string             `yaml:"description,omitempty"`
+	Deprecated      bool               `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool               `yaml:"allowEmptyValue,omitempty"`
+	Style           string             `yaml:"style,omitempty"`
+	Explode         *bool              `yaml:"explode,omitempty"`
+	AllowReserved   bool               `yaml:"allowReserved,omitempty"`
+	Schema          *Schema            `yaml:"schema,omitempty"`
+	Examples        map[string]Example `yaml:"examples,omitempty"`
+}
+
+type Example struct {
+	Summary     string `yaml:"summary,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Value       any    `yaml:"value,omitempty"`
+}
+
+type Header struct {
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Deprecated  bool    `yaml:"deprecated,omitempty"`
+	Schema      *Schema `yaml:"schema,omitempty"`
+	Ref         string  `yaml:"$ref,omitempty"`
 }
 
 type Response struct {
 	Description string             `yaml:"description"`
+	Headers     map[string]Header  `yaml:"headers,omitempty"`
 	Content     map[string]Content `yaml:"content,omitempty"`
-	Headers     map[string]Content `yaml:"headers,omitempty"`
 	Ref         string             `yaml:"$ref,omitempty"`
 }
 
 type RequestBody struct {
 	Description string             `yaml:"description,omitempty"`
 	Content     map[string]Content `yaml:"content,omitempty"`
+	Required    bool               `yaml:"required,omitempty"`
 	Ref         string             `yaml:"$ref,omitempty"`
 }
 
 type Endpoint struct {
-	Tags        []string              `yaml:"tags,omitempty"`
-	Summary     string                `yaml:"summary,omitempty"`
-	Description string                `yaml:"description,omitempty"`
-	Parameters  []Parameter           `yaml:"parameters,omitempty"`
-	RequestBody RequestBody           `yaml:"requestBody,omitempty"`
-	Deprecated  bool                  `yaml:"deprecated,omitempty"`
-	Responses   map[string]Response   `yaml:"responses,omitempty"`
-	Security    []map[string][]string `yaml:"security,omitempty"` // TODO
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Parameters  []Parameter          `yaml:"parameters,omitempty"`
+	RequestBody RequestBody          `yaml:"requestBody,omitempty"`
+	Deprecated  bool                 `yaml:"deprecated,omitempty"`
+	Responses   map[string]Response  `yaml:"responses,omitempty"`
+	Security    SecurityRequirements `yaml:"security,omitempty"`
 }
 
 type Path map[string]Endpoint
@@ -72,15 +126,17 @@ type Path map[string]Endpoint
 type Component struct {
 	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes,omitempty"`
 	Schemas         map[string]*Schema        `yaml:"schemas,omitempty"`
+	Headers         map[string]Header         `yaml:"headers,omitempty"`
 }
 
 type Doc struct {
-	OpenAPI    string          `yaml:"openapi,omitempty"`
-	Info       InfoProps       `yaml:"info,omitempty"`
-	Servers    []Server        `yaml:"servers,omitempty"`
-	BasePath   string          `yaml:"basePath,omitempty"`
-	Paths      map[string]Path `yaml:"paths,omitempty"`
-	Components Component       `yaml:"components,omitempty"`
+	OpenAPI    string               `yaml:"openapi,omitempty"`
+	Info       InfoProps            `yaml:"info,omitempty"`
+	Servers    []Server             `yaml:"servers,omitempty"`
+	BasePath   string               `yaml:"basePath,omitempty"`
+	Paths      map[string]Path      `yaml:"paths,omitempty"`
+	Components Component            `yaml:"components,omitempty"`
+	Security   SecurityRequirements `yaml:"security,omitempty"`
 }
 
 type Server struct {
@@ -88,10 +144,61 @@ type Server struct {
 }
 
 type SecurityScheme struct {
-	Scheme string `yaml:"scheme,omitempty"`
-	Type   string `yaml:"type"`
-	Name   string `yaml:"name"`
-	In     string `yaml:"in"`
+	Type             string      `yaml:"type"`
+	Description      string      `yaml:"description,omitempty"`
+	Name             string      `yaml:"name,omitempty"`
+	In               string      `yaml:"in,omitempty"`
+	Scheme           string      `yaml:"scheme,omitempty"`
+	BearerFormat     string      `yaml:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `yaml:"flows,omitempty"`
+	OpenIDConnectURL string      `yaml:"openIdConnectUrl,omitempty"`
+}
+
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `yaml:"implicit,omitempty"`
+	Password          *OAuthFlow `yaml:"password,omitempty"`
+	ClientCredentials *OAuthFlow `yaml:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode,omitempty"`
+}
+
+func (f *OAuthFlows) hasScope(scope string) bool {
+	for _, flow := range []*OAuthFlow{f.Implicit, f.Password, f.ClientCredentials, f.AuthorizationCode} {
+		if flow == nil {
+			continue
+		}
+		if _, ok := flow.Scopes[scope]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
+	RefreshURL       string            `yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes"`
+}
+
+// SecurityRequirements is a list of alternative security requirements.
+//
+//	nil list is omitted from output, empty list is rendered as `security: []`
+//	and disables security inherited from document level
+type SecurityRequirements []map[string][]string
+
+func (s SecurityRequirements) IsZero() bool {
+	return s == nil
+}
+
+// Number is numeric constraint, integral values are rendered without fraction
+type Number float64
+
+func (n Number) MarshalYAML() (any, error) {
+	if n == Number(math.Trunc(float64(n))) {
+		return int64(n), nil
+	}
+	return float64(n), nil
 }
 
 type InfoProps struct {
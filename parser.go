@@ -3,18 +3,29 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"net/http"
+	"reflect"
 	"strings"
 )
 
 const (
 	pathPrefix     = "@openapi "
 	paramPrefix    = "@openapiParam "
+	paramsPrefix   = "@openapiParams "
 	tagsPrefix     = "@openapiTags "
 	summaryPrefix  = "@openapiSummary "
 	descPrefix     = "@openapiDesc "
 	requestPrefix  = "@openapiRequest "
 	responsePrefix = "@openapiResponse "
-	securityPrefix = "@openapiSecurity"
+	problemPrefix  = "@openapiProblem "
+	headerPrefix   = "@openapiResponseHeader "
+	securityPrefix = "@openapiSecurity "
+	examplePrefix  = "@openapiExample "
+
+	headerComponentPrefix = "@openapiHeader "
+	securitySchemePrefix  = "@openapiSecurityScheme "
+	defaultSecurityPrefix = "@openapiDefaultSecurity "
 )
 
 var (
@@ -36,6 +47,17 @@ var (
 		"string":  "string",
 		"byte":    "string",
 		"[]byte":  "string",
+
+		"uuid.UUID":            "string",
+		"multipart.FileHeader": "string",
+	}
+
+	statusRangeDescriptions = map[string]string{
+		"1XX": "Informational",
+		"2XX": "Success",
+		"3XX": "Redirection",
+		"4XX": "Client error",
+		"5XX": "Server error",
 	}
 
 	formatsMap = map[string]string{
@@ -43,12 +65,46 @@ var (
 		"float32": "float",
 		"float64": "double",
 		"[]byte":  "binary",
+
+		"uuid.UUID":            "uuid",
+		"multipart.FileHeader": "binary",
+	}
+
+	// quotedPatterns patterns of numbers and booleans encoded as JSON strings
+	quotedPatterns = map[string]string{
+		"integer": "^-?[0-9]+$",
+		"number":  `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`,
+		"boolean": "^(true|false)$",
+	}
+
+	// importPathsMap import paths of base types declared in other packages
+	importPathsMap = map[string][]string{
+		"uuid.UUID":            {"github.com/google/uuid", "github.com/gofrs/uuid", "github.com/gofrs/uuid/v5"},
+		"multipart.FileHeader": {"mime/multipart"},
 	}
 )
 
+// Options configures Parser
+type Options struct {
+	// ExampleOnly keeps JSON literals as examples without inferred schema
+	ExampleOnly bool
+	// GenerateExamples synthesizes examples from schemas for requests and responses without example
+	GenerateExamples bool
+	// EmbeddedAllOf references embedded structs by allOf instead of promoting their fields
+	EmbeddedAllOf bool
+	// ValidateTag maps go-playground/validator rules of validate tag to schema constraints
+	ValidateTag bool
+	// BindingTag maps validator rules of gin binding tag to schema constraints
+	BindingTag bool
+	// InferRequired marks JSON fields which are neither pointers nor omitempty as required,
+	// fields promoted through embedded pointers are optional
+	InferRequired bool
+}
+
 type Parser struct {
 	structs *structsParser
 	doc     *Doc
+	options Options
 }
 
 func NewParser(doc *Doc, structs *structsParser) *Parser {
@@ -58,12 +114,20 @@ func NewParser(doc *Doc, structs *structsParser) *Parser {
 	}
 }
 
+// WithOptions sets parser options
+func (p *Parser) WithOptions(options Options) *Parser {
+	p.options = options
+	return p
+}
+
 func (p *Parser) parseComment(comment string, file File) (err error) {
 	splits := strings.Split(comment, "\n")
 	paths := map[string]map[string]bool{}
+	headers := map[string]map[string]Header{}
+	// examples are evaluated only for endpoints, types use @openapiExample too
+	examples := []string{}
 	endpoint := Endpoint{
 		Responses: map[string]Response{},
-		Security:  make([]map[string][]string, 0),
 	}
 
 	for _, l := range splits {
@@ -90,39 +154,105 @@ func (p *Parser) parseComment(comment string, file File) (err error) {
 		}
 
 		if strings.HasPrefix(l, paramPrefix) {
-			param, err := p.parseParam(l)
+			param, err := p.parseParam(l, file)
 			if err != nil {
 				return wrapError(err, l)
 			}
 			endpoint.Parameters = append(endpoint.Parameters, param)
 		}
 
+		if strings.HasPrefix(l, paramsPrefix) {
+			params, err := p.parseParamsStruct(l, file)
+			if err != nil {
+				return wrapError(err, l)
+			}
+			endpoint.Parameters = append(endpoint.Parameters, params...)
+		}
+
 		if strings.HasPrefix(l, requestPrefix) {
 			request, err := p.parseRequest(l, file)
 			if err != nil {
 				return wrapError(err, l)
 			}
-			endpoint.RequestBody = request
+			endpoint.RequestBody, err = mergeRequestBody(endpoint.RequestBody, request)
+			if err != nil {
+				return wrapError(err, l)
+			}
 		}
 
 		if strings.HasPrefix(l, responsePrefix) {
-			status, contentType, content, err := p.parseResponse(l, file)
+			status, response, err := p.parseResponse(l, file)
+			if err != nil {
+				return wrapError(err, l)
+			}
+
+			err = addResponse(endpoint.Responses, status, response)
+			if err != nil {
+				return wrapError(err, l)
+			}
+		}
+
+		if strings.HasPrefix(l, problemPrefix) {
+			status, response, err := p.parseProblem(l, file)
+			if err != nil {
+				return wrapError(err, l)
+			}
+
+			err = addResponse(endpoint.Responses, status, response)
+			if err != nil {
+				return wrapError(err, l)
+			}
+		}
+
+		if strings.HasPrefix(l, headerPrefix) {
+			status, name, header, err := p.parseResponseHeader(l, file)
 			if err != nil {
 				return wrapError(err, l)
 			}
+			if _, ok := headers[status]; !ok {
+				headers[status] = map[string]Header{}
+			}
+			headers[status][name] = header
+		}
 
-			endpoint.Responses[status] = Response{
-				Content: map[string]Content{
-					contentType: content,
-				},
+		if strings.HasPrefix(l, examplePrefix) {
+			examples = append(examples, l)
+		}
+
+		if strings.HasPrefix(l, headerComponentPrefix) {
+			err := p.parseHeaderComponent(l, file)
+			if err != nil {
+				return wrapError(err, l)
 			}
 		}
+
 		if strings.HasPrefix(l, securityPrefix) {
-			sec := p.parseSecurity(l)
-			if len(sec) > 0 {
-				endpoint.Security = append(endpoint.Security, sec)
+			requirements, err := p.parseSecurity(l)
+			if err != nil {
+				return wrapError(err, l)
+			}
+			if endpoint.Security == nil {
+				endpoint.Security = SecurityRequirements{}
 			}
+			endpoint.Security = append(endpoint.Security, requirements...)
+		}
+
+		if strings.HasPrefix(l, securitySchemePrefix) {
+			err := p.parseSecurityScheme(l)
+			if err != nil {
+				return wrapError(err, l)
+			}
+		}
 
+		if strings.HasPrefix(l, defaultSecurityPrefix) {
+			requirements, err := parseSecurityRequirements(strings.TrimPrefix(l, defaultSecurityPrefix))
+			if err != nil {
+				return wrapError(err, l)
+			}
+			if p.doc.Security == nil {
+				p.doc.Security = SecurityRequirements{}
+			}
+			p.doc.Security = append(p.doc.Security, requirements...)
 		}
 	}
 
@@ -138,10 +268,35 @@ func (p *Parser) parseComment(comment string, file File) (err error) {
 		}
 	}
 
+	for status := range headers {
+		response, ok := endpoint.Responses[status]
+		if !ok {
+			return fmt.Errorf("no %s %s for %s", trim(responsePrefix), status, trim(headerPrefix))
+		}
+		response.Headers = headers[status]
+		endpoint.Responses[status] = response
+	}
+
+	for _, l := range examples {
+		target, example, err := p.parseExample(l, file)
+		if err != nil {
+			return wrapError(err, l)
+		}
+
+		err = setExample(&endpoint, target, example)
+		if err != nil {
+			return wrapError(err, l)
+		}
+	}
+
 	for path := range paths {
 		for method := range paths[path] {
 			e := endpoint
 			e.Deprecated = paths[path][method]
+			if e.RequestBody.Content != nil && strIn(method, bodylessMethods) {
+				log.Printf("Request body is ignored for %s %s\n", upper(method), path)
+				e.RequestBody = RequestBody{}
+			}
 			if _, ex := p.doc.Paths[path]; !ex {
 				p.doc.Paths[path] = Path{
 					method: e,
@@ -168,8 +323,12 @@ func (p *Parser) parsePath(s string) (method, path string, deprecated bool, err
 	return
 }
 
-// parseRequest @openapiParam foo in=path, type=int, default=1, required=true, enum=1 2 3
-func (p *Parser) parseParam(s string) (Parameter, error) {
+// parseParam @openapiParam foo in=path, type=int, default=1, required=true, enum=1 2 3
+//
+//	@openapiParam ids in=query, type=array, items=int, style=form, explode=false
+//	@openapiParam filter in=query, type=object, style=deepObject, properties={status: string, from: time.Time}
+//	@openapiParam session in=cookie, type=string, deprecated
+func (p *Parser) parseParam(s string, file File) (Parameter, error) {
 	s = strings.TrimPrefix(s, paramPrefix)
 	splits := strings.SplitN(s, " ", 2)
 
@@ -178,291 +337,1084 @@ func (p *Parser) parseParam(s string) (Parameter, error) {
 		return Parameter{}, err
 	}
 
-	format := params["format"]
-	if format == "" {
-		format = formatsMap[params["type"]]
+	schema, err := p.parseParamSchema(params, file)
+	if err != nil {
+		return Parameter{}, err
 	}
 
-	if !strIn(params["type"], paramTypes) {
-		params["type"] = typesMap[params["type"]]
+	if err := checkBools(params, "required", "deprecated", "allowEmptyValue", "allowReserved", "explode"); err != nil {
+		return Parameter{}, err
 	}
 
-	_, required := params["required"]
-	if params["required"] == "" && params["in"] == "path" {
+	required, _ := getBool(params, "required")
+	if params["in"] == "path" {
 		required = true
 	}
 
-	var enum []string
-	if enumValues, ok := params["enum"]; ok {
-		enum = strings.Split(enumValues, " ")
+	param := Parameter{
+		Name:        trim(splits[0]),
+		In:          params["in"],
+		Required:    required,
+		Description: params["description"],
+		Style:       params["style"],
+		Schema:      schema,
+	}
+	param.Deprecated, _ = getBool(params, "deprecated")
+	param.AllowEmptyValue, _ = getBool(params, "allowEmptyValue")
+	param.AllowReserved, _ = getBool(params, "allowReserved")
+	if explode, ok := getBool(params, "explode"); ok {
+		param.Explode = &explode
 	}
 
-	param := Parameter{
-		Name:     trim(splits[0]),
-		In:       params["in"],
-		Required: required,
-		Schema: &Property{
-			Type:        params["type"],
-			Format:      format,
-			Example:     params["example"],
-			Default:     params["default"],
-			Description: params["description"],
-			Enum:        enum,
-		},
+	if examples := params["examples"]; examples != "" {
+		values := map[string]any{}
+		if err := json.Unmarshal([]byte(examples), &values); err != nil {
+			return param, fmt.Errorf("Invalid param 'examples'")
+		}
+
+		param.Examples = map[string]Example{}
+		for name, value := range values {
+			param.Examples[name] = Example{Value: value}
+		}
 	}
 
 	return param, validateParam(param)
 }
 
-// parseRequest @openapiRequest application/json {"foo": "bar"}
-func (p *Parser) parseRequest(s string, file File) (body RequestBody, err error) {
-	s = strings.TrimPrefix(s, requestPrefix)
-	splits := strings.SplitN(s, " ", 2)
-
-	contentType := trim(splits[0])
-	request := trim(getStr(splits, 1))
-
-	content, err := p.parseSchema(request, file)
+// parseParamSchema constructs schema of param or header from type, format, example, default, enum, items and properties params
+func (p *Parser) parseParamSchema(params map[string]string, file File) (*Schema, error) {
+	schema, err := p.parseParamType(params["type"], file)
 	if err != nil {
-		return body, err
+		return nil, err
 	}
-
-	body.Content = map[string]Content{
-		contentType: content,
+	if params["format"] != "" {
+		schema.Format = params["format"]
 	}
-	return body, validateRequest(body)
-}
 
-// parseResponse @openapiResponse 200 application/json {"foo": "bar"}
-func (p *Parser) parseResponse(s string, file File) (status string, contentType string, content Content, err error) {
-	s = strings.TrimPrefix(s, responsePrefix)
-	splits := strings.SplitN(s, " ", 3)
-
-	status = trim(splits[0])
-	contentType = trim(getStr(splits, 1))
-	response := trim(getStr(splits, 2))
+	var enum []string
+	if enumValues, ok := params["enum"]; ok {
+		enum = strings.Split(enumValues, " ")
+	}
 
-	if contentType == "application/octet-stream" {
-		content.Schema = &Schema{
-			Type:   "string",
-			Format: "binary",
+	if schema.Type == "array" && params["items"] != "" {
+		parsedType, err := p.parseType(params["items"], file)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		content, err = p.parseSchema(response, file)
+
+		schema.Items, err = p.typeToSchema(parsedType)
 		if err != nil {
-			return status, contentType, content, err
+			return nil, err
 		}
 	}
+	if enum != nil && schema.Items != nil {
+		schema.Items.setEnum(enum)
+	} else if enum != nil {
+		schema.setEnum(enum)
+	}
 
-	err = validateResponse(status, contentType, content)
-
-	return status, contentType, content, err
-}
-
-// parseSecurity @openapiSecurity api_key apiKey cookie AuthKey
-// @openapiSecurity Name Type In KeyName
-func (p *Parser) parseSecurity(commentLine string) map[string][]string {
-	secMap := make(map[string][]string)
-	splits := strings.SplitN(commentLine, " ", 5)
-
-	securityName := trim(splits[1])
-	schemeType := trim(splits[2])
-	schemeIn := trim(splits[3])
-	schemeName := trim(splits[4])
-	p.doc.Components.SecuritySchemes = make(map[string]SecurityScheme)
-	p.doc.Components.SecuritySchemes[securityName] = SecurityScheme{"", schemeType, schemeName, schemeIn}
-	secMap[securityName] = []string{}
-	return secMap
-}
-
-// parseSchema {"foo": "bar"}
-func (p *Parser) parseSchema(s string, file File) (Content, error) {
-	content := Content{}
-	if strings.HasPrefix(s, "{") {
-		if json.Valid([]byte(s)) {
-			content.Example = s
-			return content, nil
-		}
+	if params["example"] != "" {
+		schema.Example = exampleValue(schema.Type, params["example"])
+	}
+	if params["default"] != "" {
+		schema.Default = exampleValue(schema.Type, params["default"])
+	}
 
-		fields, err := parseJSONSchema(s)
+	if schema.Type == "object" && params["properties"] != "" {
+		content, err := p.parseSchema(params["properties"], file)
 		if err != nil {
-			return content, err
+			return nil, err
 		}
-
-		content.Schema = &Schema{}
-		content.Schema.Type = "object"
-		content.Schema.Properties = map[string]Property{}
-		for n, t := range fields {
-			parsedType, err := p.parseType(t, file)
-			if err != nil {
-				return content, err
-			}
-
-			property, err := p.typeToProperty(parsedType)
-			if err != nil {
-				return content, err
-			}
-			content.Schema.Properties[n] = property
+		if content.Schema == nil {
+			return nil, fmt.Errorf("Invalid param 'properties'")
 		}
 
-		return content, nil
+		schema.Properties = content.Schema.Properties
 	}
 
-	parsedType, err := p.parseType(s, file)
-	if err != nil {
-		return content, err
+	return schema, nil
+}
+
+// parseParamType returns schema for param type which is either OpenAPI type name or any Go type
+//
+//	Go types are resolved via parseType, so named types get primitive schema and structs get $ref
+func (p *Parser) parseParamType(t string, file File) (*Schema, error) {
+	if t == "" || strIn(t, paramTypes) {
+		return &Schema{Type: t}, nil
 	}
 
-	schema, err := p.parseStruct(parsedType)
+	parsedType, err := p.parseType(t, file)
 	if err != nil {
-		return content, err
+		return nil, err
 	}
-	content.Schema = schema
 
-	return content, nil
+	return p.typeToSchema(parsedType)
 }
 
-// parseAlias parses given type and returns original type name and true if type is alias
-func (p *Parser) parseAlias(s string, file File) (string, bool, error) {
-	// alias can not have package
-	if pkg := getPkg(s); pkg != "" {
-		return "", false, nil
+// parseParamsStruct @openapiParams query ListUsersFilter
+//
+//	Every struct field tagged with one of paramsTags for given location becomes a parameter
+func (p *Parser) parseParamsStruct(s string, file File) ([]Parameter, error) {
+	s = strings.TrimPrefix(s, paramsPrefix)
+	splits := strings.Fields(s)
+
+	in := getStr(splits, 0)
+	tagNames, ok := paramsTags[in]
+	if !ok {
+		return nil, fmt.Errorf("Invalid param 'in'")
 	}
 
-	structs, err := p.structs.parse(file.Pkg)
+	parsedType, err := p.parseType(getStr(splits, 1), file)
 	if err != nil {
-		return "", false, err
+		return nil, err
 	}
-
-	st, ok := structs[s]
-	if !ok {
-		return "", false, fmt.Errorf("type with name '%s' was not found in package '%s' with import path '%s'", s, file.Pkg.FSPath, file.Pkg.ImportPath)
+	if parsedType.Kind != structType {
+		return nil, fmt.Errorf("expect struct parsed type kind, got: %d", parsedType.Kind)
 	}
 
-	if st.Origin != "" {
-		return st.Origin, true, nil
+	fields, err := p.structFields(parsedType, tagNames, true)
+	if err != nil {
+		return nil, err
 	}
 
-	return "", false, nil
-}
-
-// parseStruct parses Schema from given ParsedType
-func (p *Parser) parseStruct(t *ParsedType) (*Schema, error) {
-	schema := &Schema{
-		Properties: map[string]Property{},
-	}
+	params := []Parameter{}
+	for _, field := range fields {
+		if field.IsSystem || !field.tagged {
+			continue
+		}
+		name := field.Key
 
-	switch t.Kind {
-	case arrayType, structType:
-	default:
-		return nil, fmt.Errorf("expect array or struct parsed type kind, got: %d", t.Kind)
-	}
+		tags, err := getParamsFromTag(field.Tag)
+		if err != nil {
+			return nil, err
+		}
 
-	if t.Kind == arrayType {
-		arraySchema, err := p.parseStruct(t.Nested)
+		schema, err := p.fieldSchema(field.StructField, tags, field.File)
 		if err != nil {
 			return nil, err
 		}
-		schema.Type = "array"
-		schema.Properties = nil
-		schema.Items = arraySchema
-		return schema, nil
-	}
 
-	structs, err := p.structs.parse(t.File.Pkg)
-	if err != nil {
-		return nil, err
-	}
+		if err := checkBools(tags.Openapi, "required"); err != nil {
+			return nil, err
+		}
+		required, _ := getBool(tags.Openapi, "required")
+		required = required || strIn("required", p.validateRules(tags))
+		param := Parameter{
+			Name:        name,
+			In:          in,
+			Required:    required || in == "path",
+			Description: schema.Description,
+			Schema:      schema,
+		}
+		schema.Description = ""
 
-	st, ok := structs[t.Name]
-	if !ok {
-		return nil, fmt.Errorf("struct type with name '%s' was not found in package '%s' with import path '%s'", t.Name, t.File.Pkg.FSPath, t.File.Pkg.ImportPath)
+		if err := validateParam(param); err != nil {
+			return nil, fmt.Errorf("%s: %s", field.Name, err.Error())
+		}
+		params = append(params, param)
 	}
 
-	schemaName, ok := getSchemaNameForStruct(p.doc.Components.Schemas, t.Name, st)
-	if ok {
-		return &Schema{
-			importPath: st.Pkg,
-			Ref:        fmt.Sprintf("#/components/schemas/%s", schemaName),
-		}, nil
+	return params, nil
+}
+
+// parseRequest @openapiRequest application/json {"foo": "bar"}
+//
+//	@openapiRequest required "User data" application/json,application/xml User
+//	@openapiRequest optional multipart/form-data {"file": []byte}
+func (p *Parser) parseRequest(s string, file File) (body RequestBody, err error) {
+	s = trim(strings.TrimPrefix(s, requestPrefix))
+
+	flag, rest, _ := strings.Cut(s, " ")
+	if flag == "required" || flag == "optional" {
+		body.Required = flag == "required"
+		s = trim(rest)
 	}
 
-	// add struct schema to schemas before full parsing to prevent loop calls parseStruct -> typeToProperty -> parseStruct
-	p.doc.Components.Schemas[schemaName] = schema
+	var ok bool
+	body.Description, s, ok = cutQuoted(s)
+	if !ok {
+		return body, fmt.Errorf("Invalid request description")
+	}
 
-	schema.importPath = st.Pkg
-	schema.Type = "object"
-	for i := range st.Fields {
-		tags, err := getParamsFromTag(st.Fields[i].Tag)
+	contentTypes, request := cutMediaTypes(s)
+	body.Content = map[string]Content{}
+	for _, contentType := range contentTypes {
+		content, err := p.parseContent(contentType, request, file)
 		if err != nil {
-			return nil, err
+			return body, err
 		}
 
-		if st.Fields[i].IsSystem {
-			schema.Description = tags.Description
-			continue
-		}
+		body.Content[contentType] = content
+	}
 
-		name := st.Fields[i].Name
-		tag := getTag(st.Fields[i].Tag, "json")
-		if tag == "-" {
-			continue
+	return body, validateRequest(body)
+}
+
+// mergeRequestBody merges media types, description and required flag of body into existing request body
+func mergeRequestBody(existing, body RequestBody) (RequestBody, error) {
+	if body.Description != "" {
+		existing.Description = body.Description
+	}
+	existing.Required = existing.Required || body.Required
+
+	for contentType, content := range body.Content {
+		if _, ok := existing.Content[contentType]; ok {
+			return existing, fmt.Errorf("duplicate %s content for request", contentType)
 		}
-		if tag != "" {
-			name = tag
+		if existing.Content == nil {
+			existing.Content = map[string]Content{}
 		}
+		existing.Content[contentType] = content
+	}
 
-		property := Property{
-			Type: tags.Openapi["type"],
-		}
-		if property.Type == "" {
-			parsedType, err := p.parseType(st.Fields[i].Type, t.File)
-			if err != nil {
-				return nil, err
-			}
+	return existing, nil
+}
+
+// parseResponse @openapiResponse 200 application/json {"foo": "bar"}
+//
+//	@openapiResponse 404 "User not found" application/json {"message": "Not Found"}
+//	@openapiResponse 200 application/json,application/xml User
+//	@openapiResponse 200 text/event-stream stream=Event, events=created deleted
+//	@openapiResponse 204
+//	@openapiResponse 4XX "Client error" application/json Error
+//	@openapiResponse default application/json Error
+func (p *Parser) parseResponse(s string, file File) (status string, response Response, err error) {
+	var ok bool
+	s = trim(strings.TrimPrefix(s, responsePrefix))
+	status, s, _ = strings.Cut(s, " ")
+	status = normalizeStatus(status)
+	s = trim(s)
+
+	response.Description, s, ok = cutQuoted(s)
+	if !ok {
+		return status, response, fmt.Errorf("Invalid response description")
+	}
+	if response.Description == "" {
+		response.Description = statusDescription(status)
+	}
 
-			property, err = p.typeToProperty(parsedType)
+	if s != "" {
+		contentTypes, body := cutMediaTypes(s)
+		response.Content = map[string]Content{}
+		for _, contentType := range contentTypes {
+			content, err := p.parseContent(contentType, body, file)
 			if err != nil {
-				return nil, err
+				return status, response, err
 			}
-		}
 
-		if tags.Openapi["format"] != "" {
-			property.Format = tags.Openapi["format"]
-		}
-		if tags.Example != "" {
-			property.Example = tags.Example
-		}
-		if tags.Description != "" {
-			property.Description = tags.Description
-		}
-		if tags.Openapi["default"] != "" {
-			property.Default = tags.Openapi["default"]
+			response.Content[contentType] = content
 		}
-		if tags.Enum != "" {
-			property.Enum = strings.Split(tags.Enum, ",")
-		}
-		if _, ok := tags.Openapi["required"]; ok {
-			schema.Required = append(schema.Required, name)
+	}
+
+	return status, response, validateResponse(status, response)
+}
+
+// addResponse adds response to responses, media types of responses with the same status are merged
+func addResponse(responses map[string]Response, status string, response Response) error {
+	existing, ok := responses[status]
+	if !ok {
+		responses[status] = response
+		return nil
+	}
+
+	if existing.Description == statusDescription(status) {
+		existing.Description = response.Description
+	}
+
+	for contentType, content := range response.Content {
+		if _, ok := existing.Content[contentType]; ok {
+			return fmt.Errorf("duplicate %s content for response %s", contentType, status)
 		}
-		if tags.Extensions != nil {
-			property.Extensions = tags.Extensions
+		if existing.Content == nil {
+			existing.Content = map[string]Content{}
 		}
-
-		schema.Properties[name] = property
+		existing.Content[contentType] = content
 	}
+	responses[status] = existing
 
-	return &Schema{
-		importPath: st.Pkg,
-		Ref:        fmt.Sprintf("#/components/schemas/%s", schemaName),
-	}, nil
+	return nil
 }
 
-// parseType parses given string and return ParsedType from it.
+// parseResponseHeader @openapiResponseHeader 200 X-Total-Count type=int, description=Total count
 //
-//	This function automagickally resolves pointers, types with packages and aliases.
-func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
-	t = strings.TrimPrefix(t, "*")
+//	@openapiResponseHeader 429 Retry-After type=int, required
+//	@openapiResponseHeader 200 X-Rate-Limit ref=RateLimit
+//	@openapiResponseHeader 200 RateLimit
+//	Header without params references header component with the same name.
+func (p *Parser) parseResponseHeader(s string, file File) (status, name string, header Header, err error) {
+	s = strings.TrimPrefix(s, headerPrefix)
+	splits := strings.SplitN(trim(s), " ", 3)
+
+	status = normalizeStatus(trim(splits[0]))
+	name = trim(getStr(splits, 1))
+	header, err = p.parseHeader(name, getStr(splits, 2), file)
+
+	return status, name, header, err
+}
+
+// parseHeaderComponent @openapiHeader RateLimit type=int, description=Requests limit
+func (p *Parser) parseHeaderComponent(s string, file File) error {
+	s = strings.TrimPrefix(s, headerComponentPrefix)
+	splits := strings.SplitN(trim(s), " ", 2)
+
+	name := trim(splits[0])
+	header, err := p.parseHeader(name, getStr(splits, 1), file)
+	if err != nil {
+		return err
+	}
+	if header.Ref != "" {
+		return fmt.Errorf("Invalid header 'type'")
+	}
+
+	if p.doc.Components.Headers == nil {
+		p.doc.Components.Headers = map[string]Header{}
+	}
+	p.doc.Components.Headers[name] = header
+
+	return nil
+}
+
+// parseHeader type=int, description=Total count, required, deprecated
+func (p *Parser) parseHeader(name, s string, file File) (Header, error) {
+	params, err := parseParams(trim(s))
+	if err != nil {
+		return Header{}, err
+	}
+
+	ref := params["ref"]
+	if len(params) == 0 {
+		ref = name
+	}
+	if ref != "" {
+		return Header{
+			Ref: fmt.Sprintf("#/components/headers/%s", ref),
+		}, validateHeader(name, Header{})
+	}
+
+	schema, err := p.parseParamSchema(params, file)
+	if err != nil {
+		return Header{}, err
+	}
+
+	if err := checkBools(params, "required", "deprecated"); err != nil {
+		return Header{}, err
+	}
+
+	header := Header{
+		Description: params["description"],
+		Schema:      schema,
+	}
+	header.Required, _ = getBool(params, "required")
+	header.Deprecated, _ = getBool(params, "deprecated")
+
+	return header, validateHeader(name, header)
+}
+
+// parseSecurity @openapiSecurity bearer | oauth[read:users,write:users] api_key
+//
+//	Every line (and every `|` separated part) is an alternative requirement,
+//	schemes listed in one alternative are required together.
+//	`@openapiSecurity none` marks endpoint as public.
+//	Legacy form `@openapiSecurity Name Type In KeyName` defines apiKey scheme as well.
+func (p *Parser) parseSecurity(s string) (SecurityRequirements, error) {
+	s = trim(strings.TrimPrefix(s, securityPrefix))
+
+	splits := strings.Fields(s)
+	if len(splits) == 4 && splits[1] == "apiKey" && strIn(splits[2], securitySchemeIn) {
+		err := p.addSecurityScheme(splits[0], SecurityScheme{
+			Type: splits[1],
+			In:   splits[2],
+			Name: splits[3],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return SecurityRequirements{{splits[0]: []string{}}}, nil
+	}
+
+	return parseSecurityRequirements(s)
+}
+
+// parseSecurityRequirements bearer | oauth[read:users,write:users] api_key
+func parseSecurityRequirements(s string) (SecurityRequirements, error) {
+	s = trim(s)
+	if s == "none" {
+		return SecurityRequirements{}, nil
+	}
+
+	requirements := SecurityRequirements{}
+	for _, alternative := range strings.Split(s, "|") {
+		requirement := map[string][]string{}
+		for _, item := range splitFields(alternative) {
+			name, scopes, _ := strings.Cut(item, "[")
+			if name == "" || name == "none" {
+				return nil, fmt.Errorf("Invalid security requirement")
+			}
+			if scopes != "" && !strings.HasSuffix(scopes, "]") {
+				return nil, fmt.Errorf("Invalid security scopes")
+			}
+
+			requirement[name] = strings.FieldsFunc(strings.TrimSuffix(scopes, "]"), func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+			if requirement[name] == nil {
+				requirement[name] = []string{}
+			}
+		}
+
+		if len(requirement) == 0 {
+			return nil, fmt.Errorf("Invalid security requirement")
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
+// parseSecurityScheme @openapiSecurityScheme bearer http scheme=bearer, bearerFormat=JWT
+//
+//	@openapiSecurityScheme api_key apiKey in=header, name=X-API-Key
+//	@openapiSecurityScheme oauth oauth2 flow=authorizationCode, authorizationUrl=https://..., tokenUrl=https://..., scopes={'read:users': 'Read users'}
+//	@openapiSecurityScheme oidc openIdConnect url=https://.../.well-known/openid-configuration
+//	oauth2 schemes with the same name are merged, so every flow can be declared on its own line.
+func (p *Parser) parseSecurityScheme(s string) error {
+	s = strings.TrimPrefix(s, securitySchemePrefix)
+	splits := strings.SplitN(trim(s), " ", 3)
+
+	name := trim(splits[0])
+	params, err := parseParams(getStr(splits, 2))
+	if err != nil {
+		return err
+	}
+
+	scheme := SecurityScheme{
+		Type:        trim(getStr(splits, 1)),
+		Description: params["description"],
+	}
+
+	switch scheme.Type {
+	case "apiKey":
+		scheme.In = params["in"]
+		scheme.Name = params["name"]
+	case "http":
+		scheme.Scheme = params["scheme"]
+		scheme.BearerFormat = params["bearerFormat"]
+	case "oauth2":
+		scheme.Flows, err = parseOAuthFlows(params)
+		if err != nil {
+			return err
+		}
+	case "openIdConnect":
+		scheme.OpenIDConnectURL = params["url"]
+	}
+
+	if name == "" {
+		return fmt.Errorf("Invalid security scheme name")
+	}
+	if err := validateSecurityScheme(scheme); err != nil {
+		return err
+	}
+
+	return p.addSecurityScheme(name, scheme)
+}
+
+// parseOAuthFlows flow=authorizationCode, authorizationUrl=https://..., tokenUrl=https://..., scopes=read write
+func parseOAuthFlows(params map[string]string) (*OAuthFlows, error) {
+	flow := &OAuthFlow{
+		AuthorizationURL: params["authorizationUrl"],
+		TokenURL:         params["tokenUrl"],
+		RefreshURL:       params["refreshUrl"],
+		Scopes:           map[string]string{},
+	}
+
+	scopes := params["scopes"]
+	if strings.HasPrefix(scopes, "{") {
+		if err := json.Unmarshal([]byte(scopes), &flow.Scopes); err != nil {
+			return nil, fmt.Errorf("Invalid security scopes")
+		}
+	} else {
+		for _, scope := range strings.Fields(scopes) {
+			flow.Scopes[scope] = ""
+		}
+	}
+
+	flows := &OAuthFlows{}
+	switch params["flow"] {
+	case "implicit":
+		flows.Implicit = flow
+	case "password":
+		flows.Password = flow
+	case "clientCredentials":
+		flows.ClientCredentials = flow
+	case "authorizationCode":
+		flows.AuthorizationCode = flow
+	default:
+		return nil, fmt.Errorf("Invalid OAuth flow")
+	}
+
+	return flows, validateOAuthFlow(params["flow"], flow)
+}
+
+// addSecurityScheme adds scheme to components, flows of oauth2 schemes with the same name are merged
+func (p *Parser) addSecurityScheme(name string, scheme SecurityScheme) error {
+	if p.doc.Components.SecuritySchemes == nil {
+		p.doc.Components.SecuritySchemes = map[string]SecurityScheme{}
+	}
+
+	existing, ok := p.doc.Components.SecuritySchemes[name]
+	if !ok || reflect.DeepEqual(existing, scheme) {
+		p.doc.Components.SecuritySchemes[name] = scheme
+		return nil
+	}
+
+	if existing.Type != "oauth2" || scheme.Type != "oauth2" {
+		return fmt.Errorf("security scheme '%s' already defined", name)
+	}
+
+	flows := *existing.Flows
+	for _, f := range []struct{ dst, src **OAuthFlow }{
+		{&flows.Implicit, &scheme.Flows.Implicit},
+		{&flows.Password, &scheme.Flows.Password},
+		{&flows.ClientCredentials, &scheme.Flows.ClientCredentials},
+		{&flows.AuthorizationCode, &scheme.Flows.AuthorizationCode},
+	} {
+		if *f.src == nil {
+			continue
+		}
+		if *f.dst != nil && !reflect.DeepEqual(*f.dst, *f.src) {
+			return fmt.Errorf("security scheme '%s' already defined", name)
+		}
+		*f.dst = *f.src
+	}
+	if existing.Description == "" {
+		existing.Description = scheme.Description
+	}
+	existing.Flows = &flows
+	p.doc.Components.SecuritySchemes[name] = existing
+
+	return nil
+}
+
+// parseContent parses schema for given media type
+//
+//	Content without schema of non JSON media type is a plain string (text/*) or binary string
+func (p *Parser) parseContent(contentType, s string, file File) (Content, error) {
+	if strings.HasPrefix(s, "stream=") {
+		return p.parseStream(contentType, s, file)
+	}
+	if isFormMediaType(contentType) && s != "" && !strings.HasPrefix(s, "{") {
+		return p.parseForm(s, file)
+	}
+	if s != "" || isJSONMediaType(contentType) {
+		return p.parseSchema(s, file)
+	}
+
+	content := Content{
+		Schema: &Schema{
+			Type: "string",
+		},
+	}
+	if !strings.HasPrefix(contentType, "text/") {
+		content.Schema.Format = "binary"
+	}
+
+	return content, nil
+}
+
+// parseStream stream=Event, events=created updated deleted
+//
+//	Body of streaming media type is a string, schema of every item is emitted as x-item-schema
+//	and names of Server-Sent Events as x-events
+func (p *Parser) parseStream(contentType, s string, file File) (Content, error) {
+	content := Content{}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if !strIn(strings.ToLower(trim(mediaType)), streamMediaTypes) {
+		return content, fmt.Errorf("Unsupported stream Content-Type")
+	}
+
+	params, err := parseParams(s)
+	if err != nil {
+		return content, err
+	}
+
+	item, err := p.parseSchema(params["stream"], file)
+	if err != nil {
+		return content, err
+	}
+	if item.Schema == nil {
+		return content, fmt.Errorf("Invalid stream item")
+	}
+
+	content.Schema = &Schema{
+		Type: "string",
+	}
+	content.ItemSchema = item.Schema
+	content.Events = strings.Fields(params["events"])
+	if len(content.Events) > 0 && !strings.EqualFold(trim(mediaType), "text/event-stream") {
+		return content, fmt.Errorf("Stream events are supported by text/event-stream only")
+	}
+
+	return content, nil
+}
+
+// parseForm parses inline object schema of form from struct fields with `form` tags
+//
+//	Per-part encoding is taken from `openapiEncoding:"contentType=image/png image/jpeg, headers={'X-Checksum': 'string'}"` tag
+func (p *Parser) parseForm(s string, file File) (Content, error) {
+	content := Content{}
+	parsedType, err := p.parseType(s, file)
+	if err != nil {
+		return content, err
+	}
+	if parsedType.Kind != structType {
+		return content, fmt.Errorf("expect struct parsed type kind, got: %d", parsedType.Kind)
+	}
+
+	st, err := p.lookupStruct(parsedType)
+	if err != nil {
+		return content, err
+	}
+
+	fields, err := p.structFields(parsedType, []string{"form"}, true)
+	if err != nil {
+		return content, err
+	}
+
+	content.Schema = &Schema{
+		Type:        "object",
+		Description: docDescription(st.Doc),
+		Properties:  map[string]*Schema{},
+	}
+	for _, field := range fields {
+		tags, err := getParamsFromTag(field.Tag)
+		if err != nil {
+			return content, err
+		}
+
+		if field.IsSystem {
+			if tags.Description != "" {
+				content.Schema.Description = tags.Description
+			}
+			continue
+		}
+		name := field.Key
+
+		property, err := p.fieldSchema(field.StructField, tags, field.File)
+		if err != nil {
+			return content, err
+		}
+		content.Schema.Properties[name] = property
+
+		if p.isRequired(tags) {
+			content.Schema.Required = append(content.Schema.Required, name)
+		}
+
+		if len(tags.Encoding) > 0 {
+			encoding, err := p.parseEncoding(tags.Encoding, field.File)
+			if err != nil {
+				return content, err
+			}
+			if content.Encoding == nil {
+				content.Encoding = map[string]Encoding{}
+			}
+			content.Encoding[name] = encoding
+		}
+	}
+
+	return content, nil
+}
+
+// parseEncoding contentType=image/png image/jpeg, headers={'X-Checksum': 'string'}, style=form, explode
+func (p *Parser) parseEncoding(params map[string]string, file File) (Encoding, error) {
+	if err := checkBools(params, "allowReserved", "explode"); err != nil {
+		return Encoding{}, err
+	}
+
+	encoding := Encoding{
+		ContentType: strings.Join(strings.Fields(params["contentType"]), ", "),
+		Style:       params["style"],
+	}
+	encoding.AllowReserved, _ = getBool(params, "allowReserved")
+	if explode, ok := getBool(params, "explode"); ok {
+		encoding.Explode = &explode
+	}
+
+	for _, contentType := range strings.Fields(params["contentType"]) {
+		if err := validateMediaType(contentType); err != nil {
+			return encoding, err
+		}
+	}
+
+	if params["headers"] != "" {
+		headers := map[string]string{}
+		if err := json.Unmarshal([]byte(params["headers"]), &headers); err != nil {
+			return encoding, fmt.Errorf("Invalid encoding 'headers'")
+		}
+
+		encoding.Headers = map[string]Header{}
+		for name, t := range headers {
+			header, err := p.parseHeader(name, "type="+t, file)
+			if err != nil {
+				return encoding, err
+			}
+			encoding.Headers[name] = header
+		}
+	}
+
+	return encoding, nil
+}
+
+// parseSchema {"foo": "bar"}
+func (p *Parser) parseSchema(s string, file File) (Content, error) {
+	content := Content{}
+	if (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) && json.Valid([]byte(s)) {
+		content.Example = s
+		if p.options.ExampleOnly {
+			return content, nil
+		}
+
+		schema, err := inferSchema(s)
+		if err != nil {
+			return content, err
+		}
+		content.Schema = schema
+
+		return content, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		fields, err := parseJSONSchema(s)
+		if err != nil {
+			return content, err
+		}
+
+		content.Schema = &Schema{}
+		content.Schema.Type = "object"
+		content.Schema.Properties = map[string]*Schema{}
+		for n, t := range fields {
+			parsedType, err := p.parseType(t, file)
+			if err != nil {
+				return content, err
+			}
+
+			content.Schema.Properties[n], err = p.typeToSchema(parsedType)
+			if err != nil {
+				return content, err
+			}
+		}
+
+		return content, nil
+	}
+
+	parsedType, err := p.parseType(s, file)
+	if err != nil {
+		return content, err
+	}
+
+	if parsedType.Kind == mapType {
+		content.Schema, err = p.typeToSchema(parsedType)
+		return content, err
+	}
+
+	schema, err := p.parseStruct(parsedType)
+	if err != nil {
+		return content, err
+	}
+	content.Schema = schema
+
+	return content, nil
+}
+
+// parseAlias parses given type and returns original type name and true if type is alias
+func (p *Parser) parseAlias(s string, file File) (string, bool, error) {
+	// alias can not have package
+	if pkg := getPkg(s); pkg != "" {
+		return "", false, nil
+	}
+
+	structs, err := p.structs.parse(file.Pkg)
+	if err != nil {
+		return "", false, err
+	}
+
+	st, ok := structs[s]
+	if !ok {
+		return "", false, fmt.Errorf("type with name '%s' was not found in package '%s' with import path '%s'", s, file.Pkg.FSPath, file.Pkg.ImportPath)
+	}
+
+	if st.Origin != "" {
+		return st.Origin, true, nil
+	}
+
+	return "", false, nil
+}
+
+// parseStruct parses Schema from given ParsedType
+func (p *Parser) parseStruct(t *ParsedType) (*Schema, error) {
+	schema := &Schema{
+		Properties: map[string]*Schema{},
+	}
+
+	switch t.Kind {
+	case arrayType, structType:
+	default:
+		return nil, fmt.Errorf("expect array or struct parsed type kind, got: %d", t.Kind)
+	}
+
+	if t.Kind == arrayType {
+		arraySchema, err := p.parseStruct(t.Nested)
+		if err != nil {
+			return nil, err
+		}
+		schema.Type = "array"
+		schema.Properties = nil
+		schema.Items = arraySchema
+		return schema, nil
+	}
+
+	st, err := p.lookupStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// anonymous struct is inlined
+	schemaName := ""
+	if !st.IsAnonymous {
+		if len(st.TypeParams) != len(t.Args) {
+			return nil, fmt.Errorf("generic type '%s' expects %d type arguments, got %d", t.Name, len(st.TypeParams), len(t.Args))
+		}
+
+		name, ok := getSchemaNameForType(p.doc.Components.Schemas, t, st)
+		if ok {
+			return &Schema{
+				importPath: schemaTypeID(t),
+				Ref:        fmt.Sprintf("#/components/schemas/%s", name),
+			}, nil
+		}
+
+		// built-in schema is not replaced by struct
+		if existing, ok := p.doc.Components.Schemas[name]; ok && existing.importPath == problemImportPath {
+			return nil, fmt.Errorf("schema '%s' already defined", name)
+		}
+
+		// add struct schema to schemas before full parsing to prevent loop calls parseStruct -> typeToSchema -> parseStruct
+		schemaName = name
+		p.doc.Components.Schemas[schemaName] = schema
+	}
+
+	schema.importPath = schemaTypeID(t)
+	schema.Type = "object"
+	schema.Description = docDescription(st.Doc)
+	schema.Example, err = p.structExample(st, t.File)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := p.structFields(t, []string{"json"}, !p.options.EmbeddedAllOf)
+	if err != nil {
+		return nil, err
+	}
+	if p.options.EmbeddedAllOf {
+		fields, err = p.inlineEmbedded(t, fields, []string{"json"})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	embedded := []*Schema{}
+	for _, field := range fields {
+		tags, err := getParamsFromTag(field.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.IsSystem {
+			if tags.Description != "" {
+				schema.Description = tags.Description
+			}
+			continue
+		}
+
+		if field.IsEmbedded && !field.tagged && p.options.EmbeddedAllOf {
+			parsedType, err := p.parseType(field.Type, field.File)
+			if err != nil {
+				return nil, err
+			}
+			if parsedType.Kind == structType {
+				ref, err := p.parseStruct(parsedType)
+				if err != nil {
+					return nil, err
+				}
+				embedded = append(embedded, ref)
+				continue
+			}
+		}
+
+		property, err := p.fieldSchema(field.StructField, tags, field.File)
+		if err != nil {
+			return nil, err
+		}
+		if field.hasOption("string") && tags.Openapi["type"] == "" {
+			quoteSchema(property)
+		}
+		if field.IsPointer {
+			property = nullableSchema(property)
+		}
+
+		omitted := field.hasOption("omitempty") || field.hasOption("omitzero")
+		if p.isRequired(tags) || p.options.InferRequired && !field.IsPointer && !field.viaPointer && !omitted {
+			schema.Required = append(schema.Required, field.Key)
+		}
+		schema.Properties[field.Key] = property
+	}
+
+	// embedded structs are referenced by allOf
+	if len(embedded) > 0 {
+		schema.AllOf = append(embedded, &Schema{
+			Type:       "object",
+			Properties: schema.Properties,
+			Required:   schema.Required,
+		})
+		schema.Type = ""
+		schema.Properties = nil
+		schema.Required = nil
+	}
+
+	if st.IsAnonymous {
+		return schema, nil
+	}
+
+	return &Schema{
+		importPath: schemaTypeID(t),
+		Ref:        fmt.Sprintf("#/components/schemas/%s", schemaName),
+	}, nil
+}
+
+// lookupStruct returns struct definition for given struct ParsedType
+func (p *Parser) lookupStruct(t *ParsedType) (Struct, error) {
+	structs, err := p.structs.parse(t.File.Pkg)
+	if err != nil {
+		return Struct{}, err
+	}
+
+	st, ok := structs[t.Name]
+	if !ok {
+		return Struct{}, fmt.Errorf("struct type with name '%s' was not found in package '%s' with import path '%s'", t.Name, t.File.Pkg.FSPath, t.File.Pkg.ImportPath)
+	}
+
+	return st, nil
+}
+
+// fieldSchema constructs Schema from given struct field and its openapi tags
+func (p *Parser) fieldSchema(field StructField, tags Tags, file File) (*Schema, error) {
+	schema := &Schema{
+		Type: tags.Openapi["type"],
+	}
+	if schema.Type == "" {
+		parsedType, err := p.parseType(field.Type, file)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err = p.typeToSchema(parsedType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	applyRules(schema, p.validateRules(tags))
+
+	if tags.Openapi["format"] != "" {
+		schema.Format = tags.Openapi["format"]
+	}
+	if tags.Description != "" {
+		schema.Description = tags.Description
+	} else if field.Doc != "" {
+		schema.Description = docDescription(field.Doc)
+	}
+	if tags.Enum == "-" {
+		// opt out of enum inferred from constants
+		schema.setEnum(nil)
+		if schema.Items != nil {
+			schema.Items.setEnum(nil)
+		}
+	} else if tags.Enum != "" {
+		schema.setEnum(strings.Split(tags.Enum, ","))
+	}
+	if tags.Example != "" {
+		schema.Example = exampleValue(schema.Type, tags.Example)
+	}
+	if tags.Openapi["default"] != "" {
+		schema.Default = exampleValue(schema.Type, tags.Openapi["default"])
+	}
+	for key, value := range tags.Extensions {
+		if schema.Extensions == nil {
+			schema.Extensions = map[string]string{}
+		}
+		schema.Extensions[key] = value
+	}
+
+	// siblings of $ref are ignored
+	if schema.Ref != "" && !reflect.DeepEqual(*schema, Schema{importPath: schema.importPath, Ref: schema.Ref}) {
+		schema = wrapRef(schema)
+	}
+
+	return schema, nil
+}
+
+// quoteSchema describes numbers and booleans encoded as JSON strings by string option of json tag
+func quoteSchema(schema *Schema) {
+	if !strIn(schema.Type, []string{"integer", "number", "boolean"}) {
+		return
+	}
+
+	if schema.Pattern == "" {
+		schema.Pattern = quotedPatterns[schema.Type]
+	}
+	// formats of numbers don't apply to strings
+	schema.Format = ""
+	schema.Type = "string"
+	for i := range schema.Enum {
+		schema.Enum[i] = quoteValue(schema.Enum[i])
+	}
+	schema.Default = quoteValue(schema.Default)
+	schema.Example = quoteValue(schema.Example)
+}
+
+// nullableSchema marks schema of pointer as nullable.
+//
+//	Siblings of $ref are ignored, so reference is wrapped by allOf.
+func nullableSchema(schema *Schema) *Schema {
+	schema = wrapRef(schema)
+	schema.Nullable = true
+
+	return schema
+}
+
+// wrapRef moves reference to allOf, so siblings of $ref may be set
+func wrapRef(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+
+	wrapped := *schema
+	wrapped.Ref = ""
+	wrapped.AllOf = []*Schema{{importPath: schema.importPath, Ref: schema.Ref}}
+
+	return &wrapped
+}
+
+// parseType parses given string and return ParsedType from it.
+//
+//	This function automagickally resolves pointers, types with packages and aliases.
+func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
+	t = strings.TrimPrefix(t, "*")
+
+	// type parameter of generic type
+	if arg, ok := file.TypeArgs[t]; ok {
+		return arg, nil
+	}
 
 	if isTime(t) {
 		return &ParsedType{
@@ -472,14 +1424,22 @@ func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
 		}, nil
 	}
 
-	if isBaseType(t) {
+	if isAny(t) {
 		return &ParsedType{
-			Kind: baseType,
+			Kind: anyType,
 			Name: t,
 			File: file,
 		}, nil
 	}
 
+	if name, ok := baseTypeName(t, file); ok {
+		return &ParsedType{
+			Kind: baseType,
+			Name: name,
+			File: file,
+		}, nil
+	}
+
 	if t == "map" {
 		return &ParsedType{
 			Kind: mapType,
@@ -488,6 +1448,30 @@ func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
 		}, nil
 	}
 
+	if strings.HasPrefix(t, "map[") {
+		k, v, ok := splitMapType(t)
+		if !ok {
+			return nil, fmt.Errorf("invalid map type '%s'", t)
+		}
+
+		key, err := p.parseType(k, file)
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseType(v, file)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ParsedType{
+			Kind:   mapType,
+			Name:   t,
+			File:   file,
+			Nested: value,
+			Key:    key,
+		}, nil
+	}
+
 	if strings.HasPrefix(t, "[]") {
 		nested, err := p.parseType(strings.TrimPrefix(t, "[]"), file)
 		if err != nil {
@@ -502,6 +1486,10 @@ func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
 		}, nil
 	}
 
+	if name, args, ok := splitGenericType(t); ok {
+		return p.parseGenericType(name, args, file)
+	}
+
 	pkg, t := splitName(t)
 	if pkg != "" {
 		fileWithImportedType, err := file.ParseImport(pkg, t)
@@ -517,9 +1505,29 @@ func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
 		return nil, err
 	}
 	if ok {
-		return p.parseType(ot, file)
+		parsedType, err := p.parseType(ot, file)
+		if err != nil {
+			return nil, err
+		}
+
+		// constants of named string or integer type are its enum
+		if parsedType.Kind == baseType && (typesMap[parsedType.Name] == "string" || typesMap[parsedType.Name] == "integer") {
+			enum, err := p.typeEnum(t, file)
+			if err != nil {
+				return nil, err
+			}
+			if enum != nil {
+				enumType := *parsedType
+				enumType.Enum = enum
+				return &enumType, nil
+			}
+		}
+
+		return parsedType, nil
 	}
 
+	// fields of struct are not in scope of type parameters
+	file.TypeArgs = nil
 	return &ParsedType{
 		Kind: structType,
 		Name: t,
@@ -527,6 +1535,162 @@ func (p *Parser) parseType(t string, file File) (*ParsedType, error) {
 	}, nil
 }
 
+// parseGenericType parses instantiation of generic type, for example Page[User]
+func (p *Parser) parseGenericType(name string, args []string, file File) (*ParsedType, error) {
+	parsedArgs := make([]*ParsedType, len(args))
+	for i := range args {
+		arg, err := p.parseType(args[i], file)
+		if err != nil {
+			return nil, err
+		}
+		parsedArgs[i] = arg
+	}
+
+	pkg, name := splitName(name)
+	if pkg != "" {
+		var err error
+		file, err = file.ParseImport(pkg, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	structs, err := p.structs.parse(file.Pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	st, ok := structs[name]
+	if !ok {
+		return nil, fmt.Errorf("type with name '%s' was not found in package '%s' with import path '%s'", name, file.Pkg.FSPath, file.Pkg.ImportPath)
+	}
+	if len(st.TypeParams) != len(parsedArgs) {
+		return nil, fmt.Errorf("generic type '%s' expects %d type arguments, got %d", name, len(st.TypeParams), len(parsedArgs))
+	}
+
+	file.TypeArgs = map[string]*ParsedType{}
+	for i := range st.TypeParams {
+		file.TypeArgs[st.TypeParams[i]] = parsedArgs[i]
+	}
+
+	if st.Origin != "" {
+		return p.parseType(st.Origin, file)
+	}
+
+	return &ParsedType{
+		Kind: structType,
+		Name: name,
+		File: file,
+		Args: parsedArgs,
+	}, nil
+}
+
+// getSchemaNameForType returns name of schema for struct type and whether schema with this name is already added.
+//
+//	Instantiations of generic types are named by type arguments (Page_User), names of instantiations
+//	with type arguments from different packages are qualified by packages: Page_NestedStruct -> Page_nested.NestedStruct
+func getSchemaNameForType(schemas map[string]*Schema, t *ParsedType, st Struct) (string, bool) {
+	if len(t.Args) == 0 {
+		return getSchemaNameForStruct(schemas, t.Name, st)
+	}
+
+	id := schemaTypeID(t)
+	name := ""
+	for depth := 0; ; depth++ {
+		qualified := schemaTypeName(t, depth)
+		if qualified == name {
+			// all packages are already in name
+			return name, false
+		}
+		name = qualified
+
+		existingSchema, ok := schemas[name]
+		if !ok || existingSchema.importPath == id {
+			return name, ok
+		}
+	}
+}
+
+// schemaTypeName returns name of schema for struct type, instantiations of generic types are named by type arguments (Page_User).
+//
+//	depth - number of trailing elements of import path which qualify names of structs
+func schemaTypeName(t *ParsedType, depth int) string {
+	name := qualifyName(t.File.Pkg.ImportPath, t.Name, depth)
+	for _, arg := range t.Args {
+		name += "_" + typeArgName(arg, depth)
+	}
+
+	return name
+}
+
+func typeArgName(t *ParsedType, depth int) string {
+	switch t.Kind {
+	case arrayType:
+		return typeArgName(t.Nested, depth) + "List"
+	case mapType:
+		if t.Nested == nil {
+			return "Map"
+		}
+		return typeArgName(t.Nested, depth) + "Map"
+	case timeType:
+		return "Time"
+	case anyType:
+		return "Any"
+	case structType:
+		return schemaTypeName(t, depth)
+	default:
+		_, name := splitName(t.Name)
+		return name
+	}
+}
+
+// qualifyName prefixes name with trailing elements of import path: nested.Type, tests.nested.Type
+func qualifyName(importPath, name string, depth int) string {
+	if importPath == "" {
+		return name
+	}
+
+	chunks := strings.Split(importPath, "/")
+	if depth > len(chunks) {
+		depth = len(chunks)
+	}
+
+	return strings.Join(append(chunks[len(chunks)-depth:], name), ".")
+}
+
+// schemaTypeID returns identity of struct type: import path of its package with import paths of type arguments
+func schemaTypeID(t *ParsedType) string {
+	if len(t.Args) == 0 {
+		return t.File.Pkg.ImportPath
+	}
+
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = typeArgID(arg)
+	}
+
+	return fmt.Sprintf("%s.%s[%s]", t.File.Pkg.ImportPath, t.Name, strings.Join(args, ","))
+}
+
+func typeArgID(t *ParsedType) string {
+	switch t.Kind {
+	case arrayType:
+		return "[]" + typeArgID(t.Nested)
+	case mapType:
+		if t.Nested == nil {
+			return t.Name
+		}
+		return "map[" + typeArgID(t.Key) + "]" + typeArgID(t.Nested)
+	case structType:
+		if len(t.Args) == 0 {
+			return t.File.Pkg.ImportPath + "." + t.Name
+		}
+		return schemaTypeID(t)
+	default:
+		return t.Name
+	}
+}
+
 func (p *Parser) mustParseType(t string, file File) *ParsedType {
 	resp, err := p.parseType(t, file)
 	if err != nil {
@@ -536,66 +1700,102 @@ func (p *Parser) mustParseType(t string, file File) *ParsedType {
 	return resp
 }
 
-// typeToProperty constructs Property from given ParsedType
-func (p *Parser) typeToProperty(t *ParsedType) (property Property, err error) {
+// typeToSchema constructs Schema from given ParsedType
+func (p *Parser) typeToSchema(t *ParsedType) (*Schema, error) {
+	schema := &Schema{}
 	switch t.Kind {
 	case timeType:
-		property.Type = "string"
-		property.Format = "date-time"
-		return
+		schema.Type = "string"
+		schema.Format = "date-time"
+		return schema, nil
 
 	case baseType:
-		property.Type = typesMap[t.Name]
-		property.Format = formatsMap[t.Name]
-		return
+		schema.Type = typesMap[t.Name]
+		schema.Format = formatsMap[t.Name]
+		if t.Enum != nil {
+			schema.Enum = enumValues(schema.Type, t.Enum.Values)
+			schema.EnumVarNames = t.Enum.Names
+			schema.EnumDescriptions = t.Enum.Descriptions
+		}
+		return schema, nil
 
 	case mapType:
-		property.Type = "object"
-		property.AdditionalProperties = &map[string]string{}
-		return
+		var err error
+		schema.Type = "object"
+		schema.AdditionalProperties = &Schema{}
+		if t.Nested != nil {
+			schema.AdditionalProperties, err = p.typeToSchema(t.Nested)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if t.Key != nil {
+			schema.Extensions, err = p.mapKeyExtensions(t.Key)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return schema, nil
+
+	case anyType:
+		// free-form value
+		return schema, nil
 
 	case arrayType:
-		property.Type = "array"
-		prop, err := p.typeToProperty(t.Nested)
+		items, err := p.typeToSchema(t.Nested)
 		if err != nil {
-			return property, err
+			return nil, err
 		}
 
-		property.Items = &Schema{
-			Type: prop.Type,
-		}
+		schema.Type = "array"
+		schema.Items = items
+		return schema, nil
 
-		if prop.Type == "object" {
-			property.Items.Properties = prop.Properties
-		}
+	case structType:
+		// $ref to components or inline schema of anonymous struct
+		return p.parseStruct(t)
 
-		if prop.Type == "array" {
-			property.Items.Items = prop.Items
-		}
+	default:
+		return nil, fmt.Errorf("unknown parsed type kind: %d", t.Kind)
+	}
+}
 
-		property.Items.Ref = prop.Ref
-		if property.Items.Ref != "" {
-			property.Items.Type = ""
+// mapKeyExtensions returns extensions which describe format of map keys.
+//
+//	Keys are strings in JSON, integer keys are described by pattern, keys of types with format (uuid.UUID, time.Time) by format.
+func (p *Parser) mapKeyExtensions(t *ParsedType) (map[string]string, error) {
+	switch t.Kind {
+	case baseType:
+		if typesMap[t.Name] == "integer" {
+			pattern := quotedPatterns["integer"]
+			if strings.HasPrefix(t.Name, "uint") {
+				pattern = "^[0-9]+$"
+			}
+			return map[string]string{"x-key-pattern": pattern}, nil
+		}
+		// float and bool keys are rejected by encoding/json
+		if typesMap[t.Name] != "string" {
+			break
 		}
+		if formatsMap[t.Name] != "" {
+			return map[string]string{"x-key-format": formatsMap[t.Name]}, nil
+		}
+		return nil, nil
 
-		return property, nil
+	case timeType:
+		return map[string]string{"x-key-format": "date-time"}, nil
 
 	case structType:
-		schema, err := p.parseStruct(t)
+		ok, err := p.structs.isTextMarshaler(t.File.Pkg, t.Name)
 		if err != nil {
-			return property, err
+			return nil, err
 		}
-		property.Ref = schema.Ref
-		if property.Ref == "" {
-			property.Type = "object"
+		if ok {
+			return nil, nil
 		}
-
-		property.Properties = schema.Properties
-		return property, nil
-
-	default:
-		return property, fmt.Errorf("unknown parsed type kind: %d", t.Kind)
 	}
+
+	return nil, fmt.Errorf("unsupported map key type '%s'", t.Name)
 }
 
 // parseTags @openapiTags foo, bar
@@ -626,10 +1826,85 @@ func isBaseType(t string) bool {
 	return typesMap[t] != ""
 }
 
+// baseTypeName returns name of base type, types declared in other packages are matched by import path.
+//
+//	guuid.UUID of import guuid "github.com/google/uuid" is uuid.UUID,
+//	uuid.UUID of other package is not base type.
+func baseTypeName(t string, file File) (string, bool) {
+	pkg, name := splitName(t)
+	if pkg == "" || file.ParsedFile == nil {
+		return t, isBaseType(t)
+	}
+
+	importPath, found := file.getImportPathForPkg(pkg, file.ParsedFile)
+	if !found {
+		// annotations may refer packages which are not imported by file
+		return t, isBaseType(t)
+	}
+
+	for baseName, importPaths := range importPathsMap {
+		if strings.HasSuffix(baseName, "."+name) && strIn(importPath, importPaths) {
+			return baseName, true
+		}
+	}
+	if _, ok := importPathsMap[t]; ok {
+		return t, false
+	}
+
+	return t, isBaseType(t)
+}
+
+// isJSONMediaType returns true for application/json and structured syntax suffix media types like application/problem+json
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(trim(mediaType))
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isFormMediaType returns true for media types which are encoded as form fields
+func isFormMediaType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(trim(mediaType))
+
+	return mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded"
+}
+
+func isAny(t string) bool {
+	return t == "any" || t == "interface{}"
+}
+
 func isTime(t string) bool {
 	return t == "time.Time"
 }
 
+// normalizeStatus converts status code ranges to uppercase (2xx -> 2XX)
+func normalizeStatus(status string) string {
+	if strings.ToLower(status) == "default" {
+		return "default"
+	}
+
+	return upper(status)
+}
+
+// statusDescription returns default description for response status
+func statusDescription(status string) string {
+	if status == "default" {
+		return "Default response"
+	}
+
+	if description, ok := statusRangeDescriptions[status]; ok {
+		return description
+	}
+
+	if description := http.StatusText(atoi(status)); description != "" {
+		return description
+	}
+
+	// valid code without standard text, like 299 or 520
+	return "Status " + status
+}
+
 func wrapError(err error, comment string) error {
 	return fmt.Errorf("%s (%s)", err.Error(), comment)
 }
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	goParser "go/parser"
 	"go/token"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/goccy/go-yaml"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +20,23 @@ func getFile(t *testing.T, pkg, fsPath, importPath string) File {
 	return NewFile(pkgs[pkg].Files[fsPath], filepath.Dir(fsPath), importPath)
 }
 
+// marshalJSON returns example value as JSON
+func marshalJSON(t *testing.T, value any) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// getContent returns the only media type of response and its content
+func getContent(response Response) (string, Content) {
+	for contentType, content := range response.Content {
+		return contentType, content
+	}
+
+	return "", Content{}
+}
+
 func TestParsePath(t *testing.T) {
 	parser := NewParser(nil, newStructsParser())
 	method, path, deprecated, err := parser.parsePath("@openapi GET /api/v1/test ")
@@ -34,21 +55,166 @@ func TestParsePath(t *testing.T) {
 
 func TestParseParam(t *testing.T) {
 	parser := NewParser(nil, newStructsParser())
-	param, err := parser.parseParam("@openapiParam id in=path, type=int, example=11")
+	param, err := parser.parseParam("@openapiParam id in=path, type=int, example=11", File{})
 
 	require.Nil(t, err)
 	require.Equal(t, "id", param.Name)
 	require.Equal(t, "path", param.In)
 	require.NotNil(t, param.Schema)
 	require.Equal(t, "integer", param.Schema.Type)
-	require.Equal(t, "11", param.Schema.Example)
+	require.Equal(t, int64(11), param.Schema.Example)
+
+	// Test cookie
+	param, err = parser.parseParam("@openapiParam session in=cookie, type=string, deprecated, description=Session id", File{})
+	require.Nil(t, err)
+	require.Equal(t, "cookie", param.In)
+	require.False(t, param.Required)
+	require.True(t, param.Deprecated)
+	require.Equal(t, "Session id", param.Description)
+
+	// Test boolean params
+	param, err = parser.parseParam("@openapiParam q in=query, type=string, required=false", File{})
+	require.Nil(t, err)
+	require.False(t, param.Required)
+
+	param, err = parser.parseParam("@openapiParam q in=query, type=string, required=true", File{})
+	require.Nil(t, err)
+	require.True(t, param.Required)
+
+	_, err = parser.parseParam("@openapiParam q in=query, type=string, required=yes", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid param 'required'", err.Error())
+
+	// Test array
+	param, err = parser.parseParam("@openapiParam ids in=query, type=array, items=int, style=form, explode=false, required, enum=1 2 3, examples={'one': [1], 'two': [1, 2]}", File{})
+	require.Nil(t, err)
+	require.True(t, param.Required)
+	require.Equal(t, "form", param.Style)
+	require.NotNil(t, param.Explode)
+	require.False(t, *param.Explode)
+	require.Equal(t, "array", param.Schema.Type)
+	require.Equal(t, "integer", param.Schema.Items.Type)
+	require.Equal(t, []any{int64(1), int64(2), int64(3)}, param.Schema.Items.Enum)
+	require.Nil(t, param.Schema.Enum)
+	require.Equal(t, []any{float64(1), float64(2)}, param.Examples["two"].Value)
+
+	_, err = parser.parseParam("@openapiParam ids in=query, type=array", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid param 'items'", err.Error())
+
+	// Test object
+	param, err = parser.parseParam("@openapiParam filter in=query, type=object, style=deepObject, properties={status: string, from: time.Time}", File{})
+	require.Nil(t, err)
+	require.Equal(t, "object", param.Schema.Type)
+	require.Equal(t, "string", param.Schema.Properties["status"].Type)
+	require.Equal(t, "date-time", param.Schema.Properties["from"].Format)
+
+	// Test invalid style
+	_, err = parser.parseParam("@openapiParam id in=path, type=int, style=deepObject", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid param 'style'", err.Error())
+
+	_, err = parser.parseParam("@openapiParam q in=header, type=string, allowEmptyValue", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid param 'in'", err.Error())
+
+	// Test Go types
+	param, err = parser.parseParam("@openapiParam id in=path, type=uuid.UUID", File{})
+	require.Nil(t, err)
+	require.Equal(t, "string", param.Schema.Type)
+	require.Equal(t, "uuid", param.Schema.Format)
+
+	param, err = parser.parseParam("@openapiParam from in=query, type=*time.Time", File{})
+	require.Nil(t, err)
+	require.Equal(t, "string", param.Schema.Type)
+	require.Equal(t, "date-time", param.Schema.Format)
+
+	file := getFile(t, "tests", "tests/params_structs.go", "")
+	param, err = parser.parseParam("@openapiParam status in=query, type=UserStatus, enum=new confirmed", file)
+	require.Nil(t, err)
+	require.Equal(t, "string", param.Schema.Type)
+	require.Equal(t, []any{"new", "confirmed"}, param.Schema.Enum)
+
+	param, err = parser.parseParam("@openapiParam statuses in=query, type=UserStatuses, enum=new confirmed", file)
+	require.Nil(t, err)
+	require.Equal(t, "array", param.Schema.Type)
+	require.Equal(t, "string", param.Schema.Items.Type)
+	require.Equal(t, []any{"new", "confirmed"}, param.Schema.Items.Enum)
+
+	// enum inferred from constants
+	enumFile := getFile(t, "tests", "tests/enum_structs.go", "")
+	param, err = parser.parseParam("@openapiParam status in=query, type=OrderStatus", enumFile)
+	require.Nil(t, err)
+	require.Equal(t, []any{"new", "paid", "canceled"}, param.Schema.Enum)
+	require.Equal(t, []string{"OrderNew", "OrderPaid", "OrderCanceled"}, param.Schema.EnumVarNames)
+
+	param, err = parser.parseParam("@openapiParam status in=query, type=[]OrderStatus", enumFile)
+	require.Nil(t, err)
+	require.Equal(t, []any{"new", "paid", "canceled"}, param.Schema.Items.Enum)
+
+	param, err = parser.parseParam("@openapiParam status in=query, type=OrderStatus, enum=new", enumFile)
+	require.Nil(t, err)
+	require.Equal(t, []any{"new"}, param.Schema.Enum)
+	require.Nil(t, param.Schema.EnumVarNames)
+
+	_, err = parser.parseParam("@openapiParam status in=query, type=Unknown", file)
+	require.NotNil(t, err)
+	require.Equal(t, "type with name 'Unknown' was not found in package 'tests' with import path ''", err.Error())
+}
+
+func TestParseParamsStruct(t *testing.T) {
+	parser := NewParser(&Doc{
+		OpenAPI:    "3.0.0",
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}}},
+		newStructsParser())
+	file := getFile(t, "tests", "tests/params_structs.go", "")
+
+	params, err := parser.parseParamsStruct("@openapiParams query ListUsersFilter", file)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(params))
+
+	require.Equal(t, "q", params[0].Name)
+	require.Equal(t, "query", params[0].In)
+	require.Equal(t, "Search query", params[0].Description)
+	require.Equal(t, "", params[0].Schema.Description)
+	require.Equal(t, "string", params[0].Schema.Type)
+
+	require.Equal(t, "status", params[1].Name)
+	require.Equal(t, []any{"new", "confirmed", "deleted"}, params[1].Schema.Enum)
+	require.Equal(t, "new", params[1].Schema.Default)
+
+	require.Equal(t, "ids", params[2].Name)
+	require.True(t, params[2].Required)
+	require.Equal(t, "array", params[2].Schema.Type)
+	require.Equal(t, "integer", params[2].Schema.Items.Type)
+
+	require.Equal(t, "from", params[3].Name)
+	require.False(t, params[3].Required)
+	require.Equal(t, "date-time", params[3].Schema.Format)
+
+	params, err = parser.parseParamsStruct("@openapiParams header ListUsersFilter", file)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(params))
+	require.Equal(t, "X-Token", params[0].Name)
+
+	params, err = parser.parseParamsStruct("@openapiParams path ListUsersFilter", file)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(params))
+	require.Equal(t, "group_id", params[0].Name)
+	require.True(t, params[0].Required)
+	require.Equal(t, "integer", params[0].Schema.Type)
+
+	_, err = parser.parseParamsStruct("@openapiParams body ListUsersFilter", file)
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid param 'in'", err.Error())
 }
 
 func TestParseRequest(t *testing.T) {
 	parser := NewParser(&Doc{
 		OpenAPI:    "3.0.0",
 		Paths:      map[string]Path{},
-		Components: Component{map[string]SecurityScheme{}, map[string]*Schema{}}},
+		Components: Component{SecuritySchemes: map[string]SecurityScheme{}, Schemas: map[string]*Schema{}}},
 		newStructsParser())
 
 	// Test invalid schema
@@ -56,10 +222,16 @@ func TestParseRequest(t *testing.T) {
 	require.NotNil(t, err)
 	require.Equal(t, "Invalid JSON schema", err.Error())
 
-	// Test unsupported content type
-	body, err = parser.parseRequest(`@openapiRequest text/plain {}`, File{})
+	// Test invalid content type
+	body, err = parser.parseRequest(`@openapiRequest text {}`, File{})
 	require.NotNil(t, err)
-	require.Equal(t, "Unsupported Content-Type", err.Error())
+	require.Equal(t, "Invalid Content-Type", err.Error())
+
+	// Test any valid content type
+	for _, contentType := range []string{"application/xml", "text/csv", "application/x-www-form-urlencoded", "application/problem+json", "application/vnd.api+json;charset=utf-8", "image/*"} {
+		body, err = parser.parseRequest(`@openapiRequest `+contentType+` {"foo": "bar"}`, File{})
+		require.Nil(t, err, contentType)
+	}
 
 	// Test json example
 	body, err = parser.parseRequest(`@openapiRequest application/json {"foo": "bar"}`, File{})
@@ -68,6 +240,8 @@ func TestParseRequest(t *testing.T) {
 	content, e := body.Content["application/json"]
 	require.True(t, e)
 	require.Equal(t, `{"foo": "bar"}`, content.Example)
+	require.Equal(t, "object", content.Schema.Type)
+	require.Equal(t, "string", content.Schema.Properties["foo"].Type)
 
 	// Test struct
 	body, err = parser.parseRequest(`@openapiRequest application/json User`, getFile(t, "tests", "tests/structs.go", ""))
@@ -75,7 +249,7 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "", content.Schema.Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Ref)
 
@@ -85,7 +259,7 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "array", content.Schema.Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Items.Ref)
 
@@ -95,7 +269,7 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "integer", content.Schema.Properties["id"].Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Properties["user"].Ref)
@@ -106,7 +280,7 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "array", content.Schema.Properties["user"].Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Properties["user"].Items.Ref)
@@ -117,7 +291,7 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "#/components/schemas/NestedStruct", content.Schema.Properties["user"].Ref)
 
@@ -127,52 +301,159 @@ func TestParseRequest(t *testing.T) {
 
 	content, e = body.Content["application/json"]
 	require.True(t, e)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "array", content.Schema.Properties["user"].Type)
 	require.Equal(t, "#/components/schemas/NestedStruct", content.Schema.Properties["user"].Items.Ref)
 }
 
+func TestParseRequestOptions(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+	file := getFile(t, "tests", "tests/structs.go", "")
+
+	body, err := parser.parseRequest(`@openapiRequest required "User data" application/json,application/xml User`, file)
+	require.Nil(t, err)
+	require.True(t, body.Required)
+	require.Equal(t, "User data", body.Description)
+	require.Equal(t, 2, len(body.Content))
+	require.Equal(t, "#/components/schemas/User", body.Content["application/xml"].Schema.Ref)
+
+	body, err = parser.parseRequest(`@openapiRequest optional text/plain`, file)
+	require.Nil(t, err)
+	require.False(t, body.Required)
+	require.Equal(t, "string", body.Content["text/plain"].Schema.Type)
+
+	_, err = parser.parseRequest(`@openapiRequest "User data application/json User`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid request description", err.Error())
+
+	err = parser.parseComment(`
+@openapi POST /users
+@openapi GET /users
+@openapiRequest required application/json User
+@openapiRequest "User data" multipart/form-data {"file": []byte}
+@openapiResponse 200 application/json {}
+`, file)
+	require.Nil(t, err)
+
+	body = doc.Paths["/users"]["post"].RequestBody
+	require.True(t, body.Required)
+	require.Equal(t, "User data", body.Description)
+	require.Equal(t, 2, len(body.Content))
+
+	// GET can not have request body
+	require.Nil(t, doc.Paths["/users"]["get"].RequestBody.Content)
+
+	err = parser.parseComment(`
+@openapi POST /users
+@openapiRequest application/json User
+@openapiRequest application/json []User
+@openapiResponse 200 application/json {}
+`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "duplicate application/json content for request (@openapiRequest application/json []User)", err.Error())
+}
+
+func TestParseRequestForm(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+	file := getFile(t, "tests", "tests/form_structs.go", "")
+
+	body, err := parser.parseRequest(`@openapiRequest multipart/form-data UploadForm`, file)
+	require.Nil(t, err)
+
+	content := body.Content["multipart/form-data"]
+	require.Equal(t, "object", content.Schema.Type)
+	require.Equal(t, []string{"name"}, content.Schema.Required)
+	require.Equal(t, 6, len(content.Schema.Properties))
+	require.Equal(t, "string", content.Schema.Properties["avatar"].Type)
+	require.Equal(t, "binary", content.Schema.Properties["avatar"].Format)
+	require.Equal(t, "array", content.Schema.Properties["files"].Type)
+	require.Equal(t, "binary", content.Schema.Properties["files"].Items.Format)
+	require.Equal(t, "binary", content.Schema.Properties["data"].Format)
+	require.Equal(t, "string", content.Schema.Properties["Note"].Type)
+
+	require.Equal(t, 2, len(content.Encoding))
+	require.Equal(t, "image/png, image/jpeg", content.Encoding["avatar"].ContentType)
+	require.Equal(t, "string", content.Encoding["avatar"].Headers["X-Checksum"].Schema.Type)
+	require.Equal(t, "form", content.Encoding["tags"].Style)
+	require.True(t, *content.Encoding["tags"].Explode)
+
+	body, err = parser.parseRequest(`@openapiRequest multipart/form-data, application/x-www-form-urlencoded UploadForm`, file)
+	require.Nil(t, err)
+	require.Equal(t, "binary", body.Content["multipart/form-data"].Schema.Properties["avatar"].Format)
+	require.Equal(t, "string", body.Content["application/x-www-form-urlencoded"].Schema.Properties["name"].Type)
+
+	body, err = parser.parseRequest(`@openapiRequest application/x-www-form-urlencoded UploadForm`, file)
+	require.Nil(t, err)
+	require.Equal(t, "string", body.Content["application/x-www-form-urlencoded"].Schema.Properties["name"].Type)
+
+	// struct is not added to components
+	require.Nil(t, doc.Components.Schemas["UploadForm"])
+
+	_, err = parser.parseRequest(`@openapiRequest multipart/form-data []UploadForm`, file)
+	require.NotNil(t, err)
+
+	// file header is matched by import path
+	body, err = parser.parseRequest(`@openapiRequest multipart/form-data AliasedUploadForm`, file)
+	require.Nil(t, err)
+	require.Equal(t, "binary", body.Content["multipart/form-data"].Schema.Properties["file"].Format)
+}
+
 func TestParseResponse(t *testing.T) {
 	parser := NewParser(&Doc{
 		OpenAPI:    "3.0.0",
 		Paths:      map[string]Path{},
-		Components: Component{map[string]SecurityScheme{}, map[string]*Schema{}}},
+		Components: Component{SecuritySchemes: map[string]SecurityScheme{}, Schemas: map[string]*Schema{}}},
 		newStructsParser())
 
 	// Test invalid schema
-	status, contentType, content, err := parser.parseResponse(`@openapiResponse 200 application/json {"foo", "bar"}`, File{})
+	status, response, err := parser.parseResponse(`@openapiResponse 200 application/json {"foo", "bar"}`, File{})
+	contentType, content := getContent(response)
 	require.NotNil(t, err)
 	require.Equal(t, "Invalid JSON schema", err.Error())
 
-	// Test unsupported content type
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 text/xml {"foo": "bar"}`, File{})
-	require.NotNil(t, err)
-	require.Equal(t, "Unsupported Content-Type", err.Error())
+	// Test invalid content type
+	for _, contentType := range []string{"text", "*/json", "text/xml/foo", "-text/xml"} {
+		_, _, err = parser.parseResponse(`@openapiResponse 200 `+contentType+` {"foo": "bar"}`, File{})
+		require.NotNil(t, err)
+		require.Equal(t, "Invalid Content-Type", err.Error())
+	}
 
 	// Test invalid status code
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse ddd application/json {"foo": "bar"}`, File{})
+	status, response, err = parser.parseResponse(`@openapiResponse ddd application/json {"foo": "bar"}`, File{})
+	contentType, content = getContent(response)
 	require.NotNil(t, err)
 	require.Equal(t, "Invalid HTTP status code", err.Error())
 
 	// Test json example
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json {"foo": "bar"}`, File{})
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json {"foo": "bar"}`, File{})
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
 	require.Equal(t, `{"foo": "bar"}`, content.Example)
 
 	// Test struct
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json User`, getFile(t, "tests", "tests/structs.go", ""))
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json User`, getFile(t, "tests", "tests/structs.go", ""))
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "", content.Schema.Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Ref)
 
 	// Test array of structs
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json []User`, getFile(t, "tests", "tests/structs.go", ""))
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json []User`, getFile(t, "tests", "tests/structs.go", ""))
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
@@ -180,57 +461,456 @@ func TestParseResponse(t *testing.T) {
 	require.Equal(t, "#/components/schemas/User", content.Schema.Items.Ref)
 
 	// Test json schema
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": User, "id": int}`, File{})
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": User, "id": int}`, File{})
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "integer", content.Schema.Properties["id"].Type)
 
 	// Test json schema with array
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": []User, "id": int}`, getFile(t, "tests", "tests/structs.go", ""))
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": []User, "id": int}`, getFile(t, "tests", "tests/structs.go", ""))
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "array", content.Schema.Properties["user"].Type)
 	require.Equal(t, "#/components/schemas/User", content.Schema.Properties["user"].Items.Ref)
 
 	// Test json schema with nested struct
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": nested.NestedStruct, "id": int}`, getFile(t, "tests", "tests/structs4.go", ""))
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": nested.NestedStruct, "id": int}`, getFile(t, "tests", "tests/structs4.go", ""))
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "#/components/schemas/NestedStruct", content.Schema.Properties["user"].Ref)
 
 	// Test json schema with array of nested structs
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": []nested.NestedStruct, "id": int}`, getFile(t, "tests", "tests/structs4.go", ""))
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json {"user": []nested.NestedStruct, "id": int}`, getFile(t, "tests", "tests/structs4.go", ""))
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/json", contentType)
-	require.Equal(t, "", content.Example)
+	require.Nil(t, content.Example)
 	require.Equal(t, "object", content.Schema.Type)
 	require.Equal(t, "array", content.Schema.Properties["user"].Type)
 	require.Equal(t, "#/components/schemas/NestedStruct", content.Schema.Properties["user"].Items.Ref)
 
 	// Test application/octet-stream
-	status, contentType, content, err = parser.parseResponse(`@openapiResponse 200 application/octet-stream`, File{})
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/octet-stream`, File{})
+	contentType, content = getContent(response)
 	require.Nil(t, err)
 	require.Equal(t, "200", status)
 	require.Equal(t, "application/octet-stream", contentType)
 	require.Equal(t, "string", content.Schema.Type)
 	require.Equal(t, "binary", content.Schema.Format)
+
+	// Test image range
+	status, response, err = parser.parseResponse(`@openapiResponse 200 image/*`, File{})
+	contentType, content = getContent(response)
+	require.Nil(t, err)
+	require.Equal(t, "image/*", contentType)
+	require.Equal(t, "binary", content.Schema.Format)
+
+	// Test text
+	status, response, err = parser.parseResponse(`@openapiResponse 200 text/csv`, File{})
+	contentType, content = getContent(response)
+	require.Nil(t, err)
+	require.Equal(t, "string", content.Schema.Type)
+	require.Equal(t, "", content.Schema.Format)
+
+	// Test structured syntax suffix
+	status, response, err = parser.parseResponse(`@openapiResponse 404 application/problem+json User`, getFile(t, "tests", "tests/structs.go", ""))
+	contentType, content = getContent(response)
+	require.Nil(t, err)
+	require.Equal(t, "application/problem+json", contentType)
+	require.Equal(t, "#/components/schemas/User", content.Schema.Ref)
+
+	// Test descriptions
+	require.Equal(t, "Not Found", response.Description)
+
+	status, response, err = parser.parseResponse(`@openapiResponse 404 "User not found" application/json {"message": "Not Found"}`, File{})
+	contentType, content = getContent(response)
+	require.Nil(t, err)
+	require.Equal(t, "404", status)
+	require.Equal(t, "User not found", response.Description)
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, `{"message": "Not Found"}`, content.Example)
+
+	_, _, err = parser.parseResponse(`@openapiResponse 404 "User not found application/json`, File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid response description", err.Error())
+
+	// Test bodyless
+	status, response, err = parser.parseResponse(`@openapiResponse 204`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "204", status)
+	require.Equal(t, "No Content", response.Description)
+	require.Nil(t, response.Content)
+
+	status, response, err = parser.parseResponse(`@openapiResponse 304 "Cached"`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "Cached", response.Description)
+	require.Nil(t, response.Content)
+
+	// Test codes without standard text
+	status, response, err = parser.parseResponse(`@openapiResponse 520`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "520", status)
+	require.Equal(t, "Status 520", response.Description)
+
+	// Test ranges and default
+	status, response, err = parser.parseResponse(`@openapiResponse 5xx application/json {"message": "error"}`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "5XX", status)
+	require.Equal(t, "Server error", response.Description)
+
+	status, response, err = parser.parseResponse(`@openapiResponse default "Unexpected error" application/json {"message": "error"}`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "default", status)
+	require.Equal(t, "Unexpected error", response.Description)
+
+	_, _, err = parser.parseResponse(`@openapiResponse 6XX`, File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid HTTP status code", err.Error())
+
+	// Test multiple content types
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json,application/xml User`, getFile(t, "tests", "tests/structs.go", ""))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(response.Content))
+	require.Equal(t, "#/components/schemas/User", response.Content["application/json"].Schema.Ref)
+	require.Equal(t, "#/components/schemas/User", response.Content["application/xml"].Schema.Ref)
+
+	status, response, err = parser.parseResponse(`@openapiResponse 200 application/json, application/xml User`, getFile(t, "tests", "tests/structs.go", ""))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(response.Content))
+	require.Equal(t, "#/components/schemas/User", response.Content["application/xml"].Schema.Ref)
+
+	_, _, err = parser.parseResponse(`@openapiResponse 200 application/json,text User`, getFile(t, "tests", "tests/structs.go", ""))
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid Content-Type", err.Error())
+}
+
+func TestParseResponseStream(t *testing.T) {
+	parser := NewParser(&Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}}},
+		newStructsParser())
+	file := getFile(t, "tests", "tests/structs.go", "")
+
+	_, response, err := parser.parseResponse(`@openapiResponse 200 text/event-stream stream=User, events=created deleted`, file)
+	require.Nil(t, err)
+	content := response.Content["text/event-stream"]
+	require.Equal(t, "string", content.Schema.Type)
+	require.Equal(t, "#/components/schemas/User", content.ItemSchema.Ref)
+	require.Equal(t, []string{"created", "deleted"}, content.Events)
+
+	_, response, err = parser.parseResponse(`@openapiResponse 200 application/x-ndjson stream={'id': int, 'user': User}`, file)
+	require.Nil(t, err)
+	content = response.Content["application/x-ndjson"]
+	require.Equal(t, "object", content.ItemSchema.Type)
+	require.Equal(t, "integer", content.ItemSchema.Properties["id"].Type)
+	require.Empty(t, content.Events)
+
+	_, response, err = parser.parseResponse(`@openapiResponse 200 text/event-stream, application/x-ndjson stream=User`, file)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(response.Content))
+	require.Equal(t, "#/components/schemas/User", response.Content["application/x-ndjson"].ItemSchema.Ref)
+
+	_, _, err = parser.parseResponse(`@openapiResponse 200 application/x-ndjson stream=User, events=created`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "Stream events are supported by text/event-stream only", err.Error())
+
+	_, _, err = parser.parseResponse(`@openapiResponse 200 application/json stream=User`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "Unsupported stream Content-Type", err.Error())
+}
+
+func TestParseProblem(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+	file := getFile(t, "tests", "tests/problem_structs.go", "")
+
+	status, response, err := parser.parseProblem(`@openapiProblem 404`, file)
+	require.Nil(t, err)
+	require.Equal(t, "404", status)
+	require.Equal(t, "Not Found", response.Description)
+	require.Equal(t, "#/components/schemas/Problem", response.Content["application/problem+json"].Schema.Ref)
+	require.Equal(t, 5, len(doc.Components.Schemas["Problem"].Properties))
+
+	// unquoted text is description, not type
+	status, response, err = parser.parseProblem(`@openapiProblem 404 NotFound`, file)
+	require.Nil(t, err)
+	require.Equal(t, "NotFound", response.Description)
+	require.Equal(t, "#/components/schemas/Problem", response.Content["application/problem+json"].Schema.Ref)
+
+	_, _, err = parser.parseProblem(`@openapiProblem 422 "Invalid user" ValidationProblem`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid problem extension", err.Error())
+
+	status, response, err = parser.parseProblem(`@openapiProblem 422 Invalid user type=ValidationProblem`, file)
+	require.Nil(t, err)
+	require.Equal(t, "Invalid user", response.Description)
+	require.Equal(t, "#/components/schemas/ValidationProblem", response.Content["application/problem+json"].Schema.AllOf[1].Ref)
+
+	status, response, err = parser.parseProblem(`@openapiProblem 422 "Invalid user" type=ValidationProblem`, file)
+	require.Nil(t, err)
+	require.Equal(t, "422", status)
+	require.Equal(t, "Invalid user", response.Description)
+
+	schema := response.Content["application/problem+json"].Schema
+	require.Equal(t, 2, len(schema.AllOf))
+	require.Equal(t, "#/components/schemas/Problem", schema.AllOf[0].Ref)
+	require.Equal(t, "#/components/schemas/ValidationProblem", schema.AllOf[1].Ref)
+
+	err = parser.parseComment(`
+@openapi GET /users/{id}
+@openapiResponse 404 application/json {"message": string}
+@openapiProblem 404
+@openapiProblem 5XX
+`, file)
+	require.Nil(t, err)
+
+	responses := doc.Paths["/users/{id}"]["get"].Responses
+	require.Equal(t, 2, len(responses["404"].Content))
+	require.Equal(t, "Server error", responses["5XX"].Description)
+
+	// project struct doesn't replace built-in Problem schema
+	_, err = parser.parseSchema("Problem", file)
+	require.NotNil(t, err)
+	require.Equal(t, "schema 'Problem' already defined", err.Error())
+	require.Equal(t, 5, len(doc.Components.Schemas["Problem"].Properties))
+
+	// Problem schema defined by project
+	doc.Components.Schemas["Problem"] = &Schema{Type: "object"}
+	_, _, err = parser.parseProblem(`@openapiProblem 404`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "schema 'Problem' already defined", err.Error())
+}
+
+func TestParseCommentResponses(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	err := parser.parseComment(`
+@openapi GET /users
+@openapiResponse 200 application/json []User
+@openapiResponse 200 "Users list" text/csv
+@openapiResponse 404 application/json {"message": "Not Found"}
+`, getFile(t, "tests", "tests/structs.go", ""))
+	require.Nil(t, err)
+
+	response := doc.Paths["/users"]["get"].Responses["200"]
+	require.Equal(t, "Users list", response.Description)
+	require.Equal(t, 2, len(response.Content))
+	require.Equal(t, "array", response.Content["application/json"].Schema.Type)
+	require.Equal(t, "string", response.Content["text/csv"].Schema.Type)
+	require.Equal(t, "Not Found", doc.Paths["/users"]["get"].Responses["404"].Description)
+
+	err = parser.parseComment(`
+@openapi GET /users
+@openapiResponse 200 application/json []User
+@openapiResponse 200 application/json User
+`, getFile(t, "tests", "tests/structs.go", ""))
+	require.NotNil(t, err)
+	require.Equal(t, "duplicate application/json content for response 200 (@openapiResponse 200 application/json User)", err.Error())
+}
+
+func TestParseSchemaExample(t *testing.T) {
+	parser := NewParser(&Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}}},
+		newStructsParser())
+
+	content, err := parser.parseSchema(`{"message": "Not Found", "code": 404, "rate": 0.5, "ok": false, "data": null, "tags": ["a"], "user": {"id": 1, "groups": [{"name": "admin"}]}}`, File{})
+	require.Nil(t, err)
+	require.Equal(t, `{"message": "Not Found", "code": 404, "rate": 0.5, "ok": false, "data": null, "tags": ["a"], "user": {"id": 1, "groups": [{"name": "admin"}]}}`, content.Example)
+	require.Equal(t, "object", content.Schema.Type)
+	require.Equal(t, "string", content.Schema.Properties["message"].Type)
+	require.Equal(t, "integer", content.Schema.Properties["code"].Type)
+	require.Equal(t, "number", content.Schema.Properties["rate"].Type)
+	require.Equal(t, "boolean", content.Schema.Properties["ok"].Type)
+	require.Equal(t, &Schema{}, content.Schema.Properties["data"])
+	require.Equal(t, "array", content.Schema.Properties["tags"].Type)
+	require.Equal(t, "string", content.Schema.Properties["tags"].Items.Type)
+	require.Equal(t, "object", content.Schema.Properties["user"].Type)
+	require.Equal(t, "integer", content.Schema.Properties["user"].Properties["id"].Type)
+	require.Equal(t, "object", content.Schema.Properties["user"].Properties["groups"].Items.Type)
+	require.Equal(t, "string", content.Schema.Properties["user"].Properties["groups"].Items.Properties["name"].Type)
+
+	content, err = parser.parseSchema(`map[string][]User`, getFile(t, "tests", "tests/structs.go", ""))
+	require.Nil(t, err)
+	require.Equal(t, "object", content.Schema.Type)
+	require.Equal(t, "#/components/schemas/User", content.Schema.AdditionalProperties.Items.Ref)
+
+	content, err = parser.parseSchema(`[1, 2]`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "[1, 2]", content.Example)
+	require.Equal(t, "array", content.Schema.Type)
+	require.Equal(t, "integer", content.Schema.Items.Type)
+
+	// example only
+	parser.WithOptions(Options{ExampleOnly: true})
+	content, err = parser.parseSchema(`{"message": "Not Found"}`, File{})
+	require.Nil(t, err)
+	require.Equal(t, `{"message": "Not Found"}`, content.Example)
+	require.Nil(t, content.Schema)
+}
+
+func TestParseExample(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+	file := getFile(t, "tests", "tests/example_structs.go", "")
+
+	account := `{"id":1024,"email":"john@example.com","role":2,"status":"new","tags":["a","b"],"limits":{"requests":100},"owner":{"name":"John","admin":false},"balance":-0.5}`
+
+	target, example, err := parser.parseExample(`@openapiExample 200 exampleAccount`, file)
+	require.Nil(t, err)
+	require.Equal(t, "200", target)
+	require.Equal(t, account, marshalJSON(t, example))
+
+	_, example, err = parser.parseExample(`@openapiExample 200 exampleAccounts`, file)
+	require.Nil(t, err)
+	require.Equal(t, `[`+account+`,{"id":2,"email":"jane@example.com","role":1,"status":"","tags":null,"balance":0}]`, marshalJSON(t, example))
+
+	_, _, err = parser.parseExample(`@openapiExample 200 unknownAccount`, file)
+	require.NotNil(t, err)
+
+	// promoted fields of embedded structs
+	_, example, err = parser.parseExample(`@openapiExample 200 exampleAdmin`, getFile(t, "tests", "tests/embedded_structs.go", ""))
+	require.Nil(t, err)
+	require.Equal(t, `{"id":1,"created_at":"","updated_by":"root","nested":{"ID":0,"Nested":{"ID":0}},"name":"John"}`, marshalJSON(t, example))
+
+	err = parser.parseComment(`
+@openapi POST /accounts
+@openapiRequest application/json Account
+@openapiResponse 201 application/json Account
+@openapiResponse 201 text/plain
+@openapiExample request exampleAccount
+@openapiExample 201 exampleAccount
+`, file)
+	require.Nil(t, err)
+
+	endpoint := doc.Paths["/accounts"]["post"]
+	require.Equal(t, account, marshalJSON(t, endpoint.RequestBody.Content["application/json"].Example))
+	require.Equal(t, account, marshalJSON(t, endpoint.Responses["201"].Content["application/json"].Example))
+	// example is object in YAML, not JSON string
+	data, err := yaml.Marshal(endpoint.RequestBody.Content["application/json"])
+	require.Nil(t, err)
+	require.Contains(t, string(data), "example:\n  id: 1024\n  email: john@example.com\n")
+	require.Nil(t, endpoint.Responses["201"].Content["text/plain"].Example)
+
+	// example of type keeps order of fields
+	data, err = json.Marshal(doc.Components.Schemas["Account"].Example)
+	require.Nil(t, err)
+	require.Equal(t, account, string(data))
+	data, err = yaml.Marshal(doc.Components.Schemas["Account"].Example)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(data), "id: 1024\nemail: john@example.com\nrole: 2\n"))
+
+	err = parser.parseComment(`
+@openapi GET /accounts
+@openapiResponse 200 application/json []Account
+@openapiExample 404 exampleAccount
+`, file)
+	require.NotNil(t, err)
+	require.Equal(t, "no @openapiResponse 404 for @openapiExample (@openapiExample 404 exampleAccount)", err.Error())
+}
+
+func TestGenerateExamples(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	err := parser.parseComment(`
+@openapi GET /users/{id}
+@openapiResponse 200 application/json User2
+@openapiResponse 404 application/json {"message": "Not Found"}
+@openapiResponse 204 text/plain
+`, getFile(t, "tests", "tests/struct3.go", ""))
+	require.Nil(t, err)
+
+	doc.Components.Schemas["Status"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"state":   {Type: "string", Enum: []any{"new", "confirmed"}},
+			"limit":   {Type: "integer", Default: int64(10), Example: int64(20)},
+			"page":    {Type: "integer", Default: int64(1)},
+			"min":     {Type: "number", Minimum: ptr(Number(5))},
+			"id":      {Type: "string", Format: "uuid"},
+			"payload": {},
+			// nullable reference is omitted, unresolved parts of embedded allOf are skipped
+			"parent": {AllOf: []*Schema{{Ref: "#/components/schemas/Status"}}, Nullable: true},
+			"owner":  {AllOf: []*Schema{{Ref: "#/components/schemas/Unknown"}, {Type: "object", Properties: map[string]*Schema{"name": {Type: "string"}}}}},
+			// constraints of validator rules are satisfied
+			"count": {Type: "integer", Minimum: ptr(Number(0)), ExclusiveMinimum: true},
+			"ratio": {Type: "number", Maximum: ptr(Number(-1)), ExclusiveMaximum: true},
+			"code":  {Type: "string", Pattern: rulePatterns["alpha"], MaxLength: ptr(2)},
+			"pin":   {Type: "string", MinLength: ptr(8)},
+		},
+	}
+	doc.Paths["/status"] = Path{"get": Endpoint{
+		Responses: map[string]Response{
+			"200": {Content: map[string]Content{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Status"}}}},
+		},
+	}}
+
+	generateExamples(doc)
+
+	responses := doc.Paths["/users/{id}"]["get"].Responses
+	require.Equal(t, `{"message": "Not Found"}`, responses["404"].Content["application/json"].Example)
+	require.Nil(t, responses["204"].Content["text/plain"].Example)
+
+	user := map[string]any{}
+	require.Nil(t, json.Unmarshal([]byte(marshalJSON(t, responses["200"].Content["application/json"].Example)), &user))
+	require.Equal(t, "string", user["name"])
+	require.Equal(t, float64(0), user["id"])
+	require.Equal(t, true, user["is_admin"])
+	require.Equal(t, "2024-01-01T12:00:00Z", user["created_at"])
+	require.Equal(t, map[string]any{"name": "string"}, user["accountType"])
+	// self-referencing fields are omitted
+	require.NotContains(t, user, "manager")
+	groups := user["groups"].([]any)
+	require.Equal(t, 1, len(groups))
+	require.NotContains(t, groups[0], "admin")
+	require.Equal(t, []any{"string"}, groups[0].(map[string]any)["paths"])
+
+	require.Equal(t,
+		`{"code":"ab","count":1,"id":"3fa85f64-5717-4562-b3fc-2c963f66afa6","limit":20,"min":5,"owner":{"name":"string"},"page":1,"payload":null,"pin":"stringgg","ratio":-1.5,"state":"new"}`,
+		marshalJSON(t, doc.Paths["/status"]["get"].Responses["200"].Content["application/json"].Example))
+}
+
+func TestIsJSONMediaType(t *testing.T) {
+	require.True(t, isJSONMediaType("application/json"))
+	require.True(t, isJSONMediaType("application/json; charset=utf-8"))
+	require.True(t, isJSONMediaType("application/problem+json"))
+	require.True(t, isJSONMediaType("application/vnd.github.v3+json"))
+	require.False(t, isJSONMediaType("application/xml"))
+	require.False(t, isJSONMediaType("text/plain"))
 }
 
 func TestParseStruct(t *testing.T) {
 	doc := Doc{
 		OpenAPI:    "3.0.0",
 		Paths:      map[string]Path{},
-		Components: Component{map[string]SecurityScheme{}, map[string]*Schema{}},
+		Components: Component{SecuritySchemes: map[string]SecurityScheme{}, Schemas: map[string]*Schema{}},
 	}
 	parser := NewParser(&doc, newStructsParser())
 
@@ -256,7 +936,7 @@ func TestParseStruct(t *testing.T) {
 	require.Equal(t, "uuid", user.Properties["id"].Format)
 	require.Equal(t, []string{"id", "group"}, user.Required)
 
-	require.Equal(t, []string{"admin", "manager", "user"}, user.Properties["group"].Enum)
+	require.Equal(t, []any{"admin", "manager", "user"}, user.Properties["group"].Enum)
 	require.Equal(t, "user", user.Properties["group"].Default)
 
 	require.Equal(t, "testExample", user.Properties["description"].Example)
@@ -276,6 +956,16 @@ func TestParseStruct(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "array", s.Type)
 
+	// uuid.UUID of other package is struct
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "LocalUUIDUser",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/uuid_structs.go", ""),
+	})
+	require.Nil(t, err)
+	require.Equal(t, "", doc.Components.Schemas["LocalUUIDUser"].Properties["id"].Format)
+	require.Equal(t, "#/components/schemas/UUID", doc.Components.Schemas["LocalUUIDUser"].Properties["id"].Ref)
+
 	// nested struct
 	s, err = parser.parseStruct(&ParsedType{
 		Name: "Struct4",
@@ -289,6 +979,18 @@ func TestParseStruct(t *testing.T) {
 	// even more nested struct with duplicate module name
 	require.Equal(t, "github.com/onrik/gaws/tests/nested/nested", parser.doc.Components.Schemas["nested.NestedStruct"].importPath)
 
+	// fields of unexported embedded struct of imported package are promoted
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Struct5",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/structs4.go", ""),
+	})
+	require.Nil(t, err)
+	withBase := parser.doc.Components.Schemas["WithBase"]
+	require.Equal(t, "github.com/onrik/gaws/tests/nested", withBase.importPath)
+	require.Equal(t, &Schema{Type: "integer"}, withBase.Properties["id"])
+	require.Equal(t, &Schema{Type: "string"}, withBase.Properties["name"])
+
 	// struct description
 	s, err = parser.parseStruct(&ParsedType{
 		Name: "DescriptionStruct",
@@ -298,6 +1000,44 @@ func TestParseStruct(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "Test description", parser.doc.Components.Schemas["DescriptionStruct"].Description)
 
+	// descriptions from doc comments
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "DocStruct",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/description_structs.go", ""),
+	})
+	require.Nil(t, err)
+
+	docStruct := parser.doc.Components.Schemas["DocStruct"]
+	require.Equal(t, "DocStruct is described by doc comment.", docStruct.Description)
+	require.Equal(t, "ID is identifier\nof struct", docStruct.Properties["ID"].Description)
+	require.Equal(t, "name of struct", docStruct.Properties["Name"].Description)
+	require.Equal(t, "Tag title", docStruct.Properties["Title"].Description)
+
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "TaggedDocStruct",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/description_structs.go", ""),
+	})
+	require.Nil(t, err)
+	require.Equal(t, "Tag description", parser.doc.Components.Schemas["TaggedDocStruct"].Description)
+
+	// siblings of $ref are ignored, so described reference is wrapped by allOf
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "RefDocStruct",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/description_structs.go", ""),
+	})
+	require.Nil(t, err)
+	refDoc := parser.doc.Components.Schemas["RefDocStruct"]
+	require.Equal(t, &Schema{
+		Description: "Item is described reference",
+		AllOf:       []*Schema{{Ref: "#/components/schemas/DescriptionStruct"}},
+	}, refDoc.Properties["Item"])
+	require.Equal(t, "#/components/schemas/DescriptionStruct", refDoc.Properties["Plain"].Ref)
+	require.Equal(t, "#/components/schemas/DescriptionStruct", refDoc.Properties["Ext"].AllOf[0].Ref)
+	require.Equal(t, "1", refDoc.Properties["Ext"].Extensions["x-order"])
+
 	// struct field extensions
 	s, err = parser.parseStruct(&ParsedType{
 		Name: "ExtensionsStruct",
@@ -306,67 +1046,402 @@ func TestParseStruct(t *testing.T) {
 	})
 	require.Nil(t, err)
 	require.Equal(t, map[string]string{"x-test-ext": "test", "x-test-ext2": "1"}, parser.doc.Components.Schemas["ExtensionsStruct"].Properties["ID"].Extensions)
+
+	// embedded structs
+	file := getFile(t, "tests", "tests/embedded_structs.go", "")
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Admin",
+		Kind: structType,
+		File: file,
+	})
+	require.Nil(t, err)
+
+	admin := parser.doc.Components.Schemas["Admin"]
+	require.Equal(t, 6, len(admin.Properties))
+	require.Equal(t, "integer", admin.Properties["id"].Type)
+	require.Equal(t, "string", admin.Properties["created_at"].Type)
+	require.Equal(t, "string", admin.Properties["updated_by"].Type)
+	require.Equal(t, "string", admin.Properties["name"].Type)
+	require.Equal(t, "integer", admin.Properties["level"].Type)
+	require.Equal(t, "#/components/schemas/NestedStruct", admin.Properties["nested"].Ref)
+	require.Nil(t, parser.doc.Components.Schemas["Model"])
+
+	// ambiguous fields are dropped, tagged field wins
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Conflict",
+		Kind: structType,
+		File: file,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(parser.doc.Components.Schemas["Conflict"].Properties))
+	require.Equal(t, "integer", parser.doc.Components.Schemas["Conflict"].Properties["ID"].Type)
+
+	// anonymous structs are inlined, interfaces are free-form
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Envelope",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/anonymous_structs.go", ""),
+	})
+	require.Nil(t, err)
+
+	envelope := parser.doc.Components.Schemas["Envelope"]
+	require.Equal(t, 5, len(envelope.Properties))
+	require.Equal(t, "object", envelope.Properties["meta"].Type)
+	require.Equal(t, "integer", envelope.Properties["meta"].Properties["page"].Type)
+	require.Equal(t, "array", envelope.Properties["meta"].Properties["links"].Type)
+	require.Equal(t, "string", envelope.Properties["meta"].Properties["links"].Items.Properties["href"].Type)
+	require.Equal(t, &Schema{}, envelope.Properties["payload"])
+	require.Equal(t, &Schema{}, envelope.Properties["extra"])
+	require.Equal(t, &Schema{}, envelope.Properties["raw"])
+	require.Equal(t, "array", envelope.Properties["items"].Type)
+	require.Equal(t, &Schema{}, envelope.Properties["items"].Items)
+	require.Nil(t, parser.doc.Components.Schemas["Envelope{Meta}"])
+
+	// typed maps
+	mapsFile := getFile(t, "tests", "tests/map_structs.go", "")
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Catalog",
+		Kind: structType,
+		File: mapsFile,
+	})
+	require.Nil(t, err)
+
+	catalog := parser.doc.Components.Schemas["Catalog"]
+	require.Equal(t, "object", catalog.Properties["users"].Type)
+	require.Equal(t, "#/components/schemas/Item", catalog.Properties["users"].AdditionalProperties.Ref)
+	require.Equal(t, "array", catalog.Properties["scores"].AdditionalProperties.Type)
+	require.Equal(t, "integer", catalog.Properties["scores"].AdditionalProperties.Items.Type)
+	require.Equal(t, "#/components/schemas/Item", catalog.Properties["by_id"].AdditionalProperties.Ref)
+	require.Equal(t, map[string]string{"x-key-pattern": "^-?[0-9]+$"}, catalog.Properties["by_id"].Extensions)
+	require.Equal(t, map[string]string{"x-key-pattern": "^[0-9]+$"}, catalog.Properties["by_code"].Extensions)
+	require.Equal(t, map[string]string{"x-key-format": "date-time"}, catalog.Properties["by_time"].Extensions)
+	require.Nil(t, catalog.Properties["by_key"].Extensions)
+	require.Equal(t, "boolean", catalog.Properties["nested"].AdditionalProperties.AdditionalProperties.Type)
+	require.Equal(t, "string", catalog.Properties["meta"].AdditionalProperties.Properties["note"].Type)
+	require.Equal(t, &Schema{}, catalog.Properties["free"].AdditionalProperties)
+	require.Equal(t, "integer", catalog.Properties["counters"].AdditionalProperties.Type)
+
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "InvalidKeyMap",
+		Kind: structType,
+		File: mapsFile,
+	})
+	require.NotNil(t, err)
+	require.Equal(t, "unsupported map key type 'Model'", err.Error())
+
+	// keys which encoding/json can't marshal, MarshalText of pointer is not used for keys
+	for name, key := range map[string]string{"PtrKeyMap": "PtrKey", "FloatKeyMap": "float64", "BoolKeyMap": "bool"} {
+		_, err = parser.parseStruct(&ParsedType{
+			Name: name,
+			Kind: structType,
+			File: mapsFile,
+		})
+		require.NotNil(t, err)
+		require.Equal(t, fmt.Sprintf("unsupported map key type '%s'", key), err.Error())
+	}
+
+	// generic structs
+	genericFile := getFile(t, "tests", "tests/generic_structs.go", "")
+	content, err := parser.parseSchema("Result[nested.NestedStruct,string]", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, "#/components/schemas/Result_NestedStruct_string", content.Schema.Ref)
+
+	result := parser.doc.Components.Schemas["Result_NestedStruct_string"]
+	require.Equal(t, "#/components/schemas/NestedStruct", result.Properties["data"].Ref)
+	require.Equal(t, "string", result.Properties["errors"].Items.Type)
+	require.Equal(t, "#/components/schemas/Page_NestedStruct", result.Properties["pages"].Ref)
+	require.Equal(t, "#/components/schemas/NestedStruct", result.Properties["index"].AdditionalProperties.Ref)
+
+	page := parser.doc.Components.Schemas["Page_NestedStruct"]
+	require.Equal(t, "#/components/schemas/NestedStruct", page.Properties["items"].Items.Ref)
+	// pointer reference is wrapped by allOf to be nullable
+	require.Equal(t, "#/components/schemas/NestedStruct", page.Properties["next"].AllOf[0].Ref)
+	require.True(t, page.Properties["next"].Nullable)
+	require.Equal(t, "#/components/schemas/Meta", page.Properties["meta"].Ref)
+
+	// type arguments with the same name from different packages
+	for i := 0; i < 2; i++ {
+		content, err = parser.parseSchema("DeepNestedPage", genericFile)
+		require.Nil(t, err)
+		require.Equal(t, "#/components/schemas/Page_nested.NestedStruct", content.Schema.Ref)
+	}
+	require.Equal(t, "#/components/schemas/nested.NestedStruct", parser.doc.Components.Schemas["Page_nested.NestedStruct"].Properties["items"].Items.Ref)
+	require.Equal(t, "#/components/schemas/NestedStruct", page.Properties["items"].Items.Ref)
+
+	content, err = parser.parseSchema("Node[Meta]", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, "#/components/schemas/Node_Meta", parser.doc.Components.Schemas["Node_Meta"].Properties["children"].Items.Ref)
+
+	content, err = parser.parseSchema("Paged[[]Meta]", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, "#/components/schemas/Paged_MetaList", content.Schema.Ref)
+	require.Equal(t, 5, len(parser.doc.Components.Schemas["Paged_MetaList"].Properties))
+	require.Equal(t, "#/components/schemas/Meta", parser.doc.Components.Schemas["Paged_MetaList"].Properties["items"].Items.Items.Ref)
+
+	content, err = parser.parseSchema("UserPage", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, "#/components/schemas/Page_Meta", content.Schema.Ref)
+
+	content, err = parser.parseSchema("List[Meta]", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, "array", content.Schema.Type)
+	require.Equal(t, "#/components/schemas/Meta", content.Schema.Items.Ref)
+
+	_, err = parser.parseSchema("Page", genericFile)
+	require.NotNil(t, err)
+	require.Equal(t, "generic type 'Page' expects 1 type arguments, got 0", err.Error())
+
+	_, example, err := parser.parseExample("@openapiExample 200 examplePage", genericFile)
+	require.Nil(t, err)
+	require.Equal(t, `{"items":[{"cursor":"a"}],"total":1,"meta":{"cursor":""}}`, marshalJSON(t, example))
+
+	// enums inferred from constants
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Order",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/enum_structs.go", ""),
+	})
+	require.Nil(t, err)
+
+	order := parser.doc.Components.Schemas["Order"]
+	require.Equal(t, []any{"new", "paid", "canceled"}, order.Properties["status"].Enum)
+	require.Equal(t, []string{"OrderNew", "OrderPaid", "OrderCanceled"}, order.Properties["status"].EnumVarNames)
+	require.Equal(t, []string{"OrderNew is created order", "paid by customer", ""}, order.Properties["status"].EnumDescriptions)
+	require.Equal(t, []any{"new", "paid", "canceled"}, order.Properties["history"].Items.Enum)
+	require.Equal(t, "integer", order.Properties["priority"].Type)
+	require.Equal(t, []any{int64(1), int64(2), int64(4)}, order.Properties["priority"].Enum)
+	require.Equal(t, []string{"PriorityLow", "PriorityHigh", "PriorityUrgent"}, order.Properties["priority"].EnumVarNames)
+	require.Equal(t, []string{"", "", "PriorityUrgent is typed by type checker"}, order.Properties["priority"].EnumDescriptions)
+	require.Equal(t, &Schema{Type: "string"}, order.Properties["legacy"])
+	require.Equal(t, []any{"new", "paid"}, order.Properties["custom"].Enum)
+	require.Nil(t, order.Properties["custom"].EnumVarNames)
+	// enums of types from GOROOT and dependencies are not inferred
+	require.Equal(t, &Schema{Type: "integer"}, order.Properties["timeout"])
+
+	// embedded structs by allOf
+	doc.Components.Schemas = map[string]*Schema{}
+	parser.WithOptions(Options{EmbeddedAllOf: true})
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "Admin",
+		Kind: structType,
+		File: file,
+	})
+	require.Nil(t, err)
+
+	admin = parser.doc.Components.Schemas["Admin"]
+	require.Equal(t, "", admin.Type)
+	require.Equal(t, 2, len(admin.AllOf))
+	require.Equal(t, "#/components/schemas/Model", admin.AllOf[0].Ref)
+	// auditInfo has name shadowed by Admin, so its fields are inlined instead of reference
+	require.Equal(t, 4, len(admin.AllOf[1].Properties))
+	require.Equal(t, &Schema{Type: "string"}, admin.AllOf[1].Properties["updated_by"])
+	require.Equal(t, "#/components/schemas/NestedStruct", admin.AllOf[1].Properties["nested"].Ref)
+	require.Nil(t, parser.doc.Components.Schemas["auditInfo"])
+	require.Equal(t, 2, len(parser.doc.Components.Schemas["Model"].Properties))
 }
 
-func TestTypeToProperty(t *testing.T) {
+func TestParseStructRules(t *testing.T) {
+	newParser := func(options Options) *Parser {
+		return NewParser(&Doc{
+			Paths:      map[string]Path{},
+			Components: Component{Schemas: map[string]*Schema{}},
+		}, newStructsParser()).WithOptions(options)
+	}
+	signup := &ParsedType{
+		Name: "SignupRequest",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/rules_structs.go", ""),
+	}
+
+	// validation tags are disabled by default
+	parser := newParser(Options{})
+	_, err := parser.parseStruct(signup)
+	require.Nil(t, err)
+	schema := parser.doc.Components.Schemas["SignupRequest"]
+	require.Nil(t, schema.Required)
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["name"])
+
+	parser = newParser(Options{ValidateTag: true})
+	_, err = parser.parseStruct(signup)
+	require.Nil(t, err)
+	schema = parser.doc.Components.Schemas["SignupRequest"]
+	require.Equal(t, []string{"email", "name"}, schema.Required)
+	require.Equal(t, "email", schema.Properties["email"].Format)
+	require.Equal(t, &Schema{Type: "string", MinLength: ptr(3), MaxLength: ptr(64), Pattern: "^[a-zA-Z0-9]+$"}, schema.Properties["name"])
+	require.Equal(t, &Schema{Type: "string", MinLength: ptr(6), MaxLength: ptr(6), Pattern: "^[0-9]+$"}, schema.Properties["code"])
+	require.Equal(t, []any{"admin", "user", "super user"}, schema.Properties["role"].Enum)
+	require.Equal(t, []any{"free", "pro", "team"}, schema.Properties["plan"].Enum)
+	require.Equal(t, &Schema{Type: "integer", Minimum: ptr(Number(0)), Maximum: ptr(Number(150)), ExclusiveMaximum: true}, schema.Properties["age"])
+	require.Equal(t, &Schema{Type: "number", Format: "double", Minimum: ptr(Number(0.5)), ExclusiveMinimum: true, Maximum: ptr(Number(10))}, schema.Properties["score"])
+	// rules after dive are applied to elements
+	require.Equal(t, ptr(1), schema.Properties["tags"].MinItems)
+	require.Equal(t, ptr(5), schema.Properties["tags"].MaxItems)
+	require.Nil(t, schema.Properties["tags"].Items.Enum)
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["website"])
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["password"])
+	require.Equal(t, "hostname", schema.Properties["homepage"].Format)
+
+	parser = newParser(Options{BindingTag: true})
+	_, err = parser.parseStruct(signup)
+	require.Nil(t, err)
+	schema = parser.doc.Components.Schemas["SignupRequest"]
+	require.Equal(t, []string{"password"}, schema.Required)
+	require.Equal(t, &Schema{Type: "string", MinLength: ptr(8)}, schema.Properties["password"])
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["name"])
+}
+
+func TestParseStructNullable(t *testing.T) {
+	newParser := func(options Options) *Parser {
+		return NewParser(&Doc{
+			Paths:      map[string]Path{},
+			Components: Component{Schemas: map[string]*Schema{}},
+		}, newStructsParser()).WithOptions(options)
+	}
+	profile := &ParsedType{
+		Name: "Profile",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/nullable_structs.go", ""),
+	}
+
+	parser := newParser(Options{})
+	_, err := parser.parseStruct(profile)
+	require.Nil(t, err)
+	schema := parser.doc.Components.Schemas["Profile"]
+	require.Equal(t, []string{"email"}, schema.Required)
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["name"])
+	require.Equal(t, &Schema{Type: "string", Nullable: true}, schema.Properties["nickname"])
+	// siblings of $ref are ignored, so reference is wrapped by allOf
+	avatar := schema.Properties["avatar"]
+	require.Equal(t, "", avatar.Ref)
+	require.Equal(t, "#/components/schemas/Avatar", avatar.AllOf[0].Ref)
+	require.True(t, avatar.Nullable)
+	require.Equal(t, "Profile picture", avatar.Description)
+
+	parser = newParser(Options{InferRequired: true})
+	_, err = parser.parseStruct(profile)
+	require.Nil(t, err)
+	schema = parser.doc.Components.Schemas["Profile"]
+	require.Equal(t, []string{"name", "email", "tags"}, schema.Required)
+
+	// fields promoted through embedded pointer are not inferred as required
+	for name, required := range map[string][]string{"PtrEmbedded": {"name"}, "ValueEmbedded": {"id", "name"}} {
+		_, err = parser.parseStruct(&ParsedType{
+			Name: name,
+			Kind: structType,
+			File: getFile(t, "tests", "tests/nullable_structs.go", ""),
+		})
+		require.Nil(t, err)
+		require.Equal(t, required, parser.doc.Components.Schemas[name].Required, name)
+	}
+
+	// embedded pointer is not referenced by allOf, referenced schema would require its fields
+	parser = newParser(Options{InferRequired: true, EmbeddedAllOf: true})
+	_, err = parser.parseStruct(&ParsedType{
+		Name: "PtrEmbedded",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/nullable_structs.go", ""),
+	})
+	require.Nil(t, err)
+	schema = parser.doc.Components.Schemas["PtrEmbedded"]
+	require.Nil(t, schema.AllOf)
+	require.Equal(t, []string{"name"}, schema.Required)
+	require.Equal(t, &Schema{Type: "integer"}, schema.Properties["id"])
+	require.Nil(t, parser.doc.Components.Schemas["Base"])
+}
+
+func TestParseStructJSONOptions(t *testing.T) {
+	parser := NewParser(&Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}, newStructsParser()).WithOptions(Options{InferRequired: true})
+	_, err := parser.parseStruct(&ParsedType{
+		Name: "JSONOptions",
+		Kind: structType,
+		File: getFile(t, "tests", "tests/json_tag_structs.go", ""),
+	})
+	require.Nil(t, err)
+
+	schema := parser.doc.Components.Schemas["JSONOptions"]
+	keys := []string{}
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	require.ElementsMatch(t, []string{"id", "labeled", "count", "ratio", "enabled", "title", "level", "-", "Invalid", "Name", "name", "point", "empty", "nil", "optional"}, keys)
+	require.Equal(t, []string{"id", "labeled", "count", "title", "level", "-", "Invalid", "Name", "name"}, schema.Required)
+
+	// numbers and booleans are quoted by string option
+	require.Equal(t, &Schema{Type: "string", Pattern: "^-?[0-9]+$"}, schema.Properties["count"])
+	require.Equal(t, &Schema{Type: "string", Pattern: quotedPatterns["number"]}, schema.Properties["ratio"])
+	require.Equal(t, &Schema{Type: "string", Pattern: "^(true|false)$", Nullable: true}, schema.Properties["enabled"])
+	require.Equal(t, &Schema{Type: "string"}, schema.Properties["title"])
+	require.Equal(t, &Schema{Type: "string", Pattern: "^-?[0-9]+$", Default: "1"}, schema.Properties["level"])
+	require.Equal(t, "#/components/schemas/labeled", schema.Properties["labeled"].Ref)
+
+	// example is encoded like json.Marshal does
+	data, err := json.Marshal(schema.Example)
+	require.Nil(t, err)
+	require.Equal(t, `{"id":0,"labeled":{"label":""},"count":"5","enabled":null,"title":"\"t\"","level":"0","-":"","Invalid":"","Name":"","name":"","empty":[]}`, string(data))
+}
+
+func TestTypeToSchema(t *testing.T) {
 	parser := NewParser(&Doc{
 		OpenAPI:    "3.0.0",
 		Paths:      map[string]Path{},
-		Components: Component{map[string]SecurityScheme{}, map[string]*Schema{}}}, newStructsParser())
+		Components: Component{SecuritySchemes: map[string]SecurityScheme{}, Schemas: map[string]*Schema{}}}, newStructsParser())
 
-	p, err := parser.typeToProperty(parser.mustParseType("int", File{}))
+	p, err := parser.typeToSchema(parser.mustParseType("int", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "integer", p.Type)
 	require.Equal(t, "", p.Format)
 
-	p, err = parser.typeToProperty(parser.mustParseType("*int", File{}))
+	p, err = parser.typeToSchema(parser.mustParseType("*int", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "integer", p.Type)
 	require.Equal(t, "", p.Format)
 
-	p, err = parser.typeToProperty(parser.mustParseType("string", File{}))
+	p, err = parser.typeToSchema(parser.mustParseType("string", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "string", p.Type)
 	require.Equal(t, "", p.Format)
 
-	p, err = parser.typeToProperty(parser.mustParseType("time.Time", File{}))
+	p, err = parser.typeToSchema(parser.mustParseType("time.Time", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "string", p.Type)
 	require.Equal(t, "date-time", p.Format)
 
-	p, err = parser.typeToProperty(parser.mustParseType("*time.Time", File{}))
+	p, err = parser.typeToSchema(parser.mustParseType("*time.Time", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "string", p.Type)
 	require.Equal(t, "date-time", p.Format)
 
-	p, err = parser.typeToProperty(parser.mustParseType("[]string", File{}))
+	p, err = parser.typeToSchema(parser.mustParseType("[]string", File{}))
 	require.Nil(t, err)
 	require.Equal(t, "array", p.Type)
 	require.NotNil(t, p.Items)
 	require.Equal(t, "string", p.Items.Type)
 
-	p, err = parser.typeToProperty(parser.mustParseType("User", getFile(t, "tests", "tests/structs.go", "")))
+	p, err = parser.typeToSchema(parser.mustParseType("User", getFile(t, "tests", "tests/structs.go", "")))
 	require.Nil(t, err)
 	require.Equal(t, "#/components/schemas/User", p.Ref)
 
 	// alias
-	p, err = parser.typeToProperty(parser.mustParseType("Alias", getFile(t, "tests", "tests/alias_structs.go", "")))
+	p, err = parser.typeToSchema(parser.mustParseType("Alias", getFile(t, "tests", "tests/alias_structs.go", "")))
 	require.Nil(t, err)
 	require.Equal(t, "#/components/schemas/StructForAlias", p.Ref)
-	require.Equal(t, map[string]Property{"name": {Type: "string"}}, parser.doc.Components.Schemas["StructForAlias"].Properties)
+	require.Equal(t, map[string]*Schema{"name": {Type: "string"}}, parser.doc.Components.Schemas["StructForAlias"].Properties)
 
 	// nested alias
-	p, err = parser.typeToProperty(parser.mustParseType("NestedAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
+	p, err = parser.typeToSchema(parser.mustParseType("NestedAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
 	require.Nil(t, err)
 	require.Equal(t, "#/components/schemas/NestedStruct", p.Ref)
 
 	// alias for simple type
-	p, err = parser.typeToProperty(parser.mustParseType("SimpleAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
+	p, err = parser.typeToSchema(parser.mustParseType("SimpleAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
 	require.Nil(t, err)
 	require.Equal(t, "string", p.Type)
 
 	// alias for nested simple type
-	p, err = parser.typeToProperty(parser.mustParseType("NestedSimpleAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
+	p, err = parser.typeToSchema(parser.mustParseType("NestedSimpleAlias", getFile(t, "tests", "tests/alias_structs.go", "")))
 	require.Nil(t, err)
 	require.Equal(t, "", p.Ref)
 	require.Equal(t, "string", p.Type)
@@ -381,9 +1456,203 @@ func TestParseType(t *testing.T) {
 	parser := NewParser(&Doc{
 		OpenAPI:    "3.0.0",
 		Paths:      map[string]Path{},
-		Components: Component{map[string]SecurityScheme{}, map[string]*Schema{}}}, newStructsParser())
+		Components: Component{SecuritySchemes: map[string]SecurityScheme{}, Schemas: map[string]*Schema{}}}, newStructsParser())
 
 	_, err := parser.parseType("User", getFile(t, "nested", "tests/nested/nested.go", "github.com/onrik/gaws/tests/nested"))
 	require.NotNil(t, err)
 	require.Equal(t, "type with name 'User' was not found in package 'tests/nested' with import path 'github.com/onrik/gaws/tests/nested'", err.Error())
 }
+
+func TestParseResponseHeader(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	status, name, header, err := parser.parseResponseHeader("@openapiResponseHeader 200 X-Total-Count type=int, description=Total count, required", File{})
+	require.Nil(t, err)
+	require.Equal(t, "200", status)
+	require.Equal(t, "X-Total-Count", name)
+	require.Equal(t, "Total count", header.Description)
+	require.True(t, header.Required)
+	require.Equal(t, "integer", header.Schema.Type)
+
+	status, name, header, err = parser.parseResponseHeader("@openapiResponseHeader 2xx Location type=string, format=uri", File{})
+	require.Nil(t, err)
+	require.Equal(t, "2XX", status)
+	require.Equal(t, "uri", header.Schema.Format)
+
+	_, _, header, err = parser.parseResponseHeader("@openapiResponseHeader 429 X-Rate-Limit ref=RateLimit", File{})
+	require.Nil(t, err)
+	require.Equal(t, "#/components/headers/RateLimit", header.Ref)
+
+	_, _, header, err = parser.parseResponseHeader("@openapiResponseHeader 429 RateLimit", File{})
+	require.Nil(t, err)
+	require.Equal(t, "#/components/headers/RateLimit", header.Ref)
+
+	_, _, _, err = parser.parseResponseHeader("@openapiResponseHeader 200 Content-Type type=string", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Content-Type header is described by media type", err.Error())
+
+	_, _, _, err = parser.parseResponseHeader("@openapiResponseHeader 200 X(Total) type=int", File{})
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid header name", err.Error())
+
+	err = parser.parseComment(`
+@openapiHeader RateLimit type=int, description=Requests limit
+`, File{})
+	require.Nil(t, err)
+	require.Equal(t, "Requests limit", doc.Components.Headers["RateLimit"].Description)
+
+	err = parser.parseComment(`
+@openapi GET /users
+@openapiResponseHeader 200 X-Total-Count type=int
+@openapiResponseHeader 200 RateLimit
+@openapiResponseHeader 200 X-Unknown ref=Unknown
+@openapiResponse 200 application/json {}
+`, File{})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(doc.Paths["/users"]["get"].Responses["200"].Headers))
+
+	errors := validateDoc(doc)
+	require.Equal(t, 1, len(errors))
+	require.Equal(t, "unknown header component 'Unknown' (GET /users)", errors[0].Error())
+
+	err = parser.parseComment(`
+@openapi GET /users
+@openapiResponseHeader 201 Location type=string
+@openapiResponse 200 application/json {}
+`, File{})
+	require.NotNil(t, err)
+	require.Equal(t, "no @openapiResponse 201 for @openapiResponseHeader", err.Error())
+}
+
+func TestParseSecurity(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	// legacy form
+	requirements, err := parser.parseSecurity("@openapiSecurity api_key apiKey cookie AuthKey")
+	require.Nil(t, err)
+	require.Equal(t, SecurityRequirements{{"api_key": {}}}, requirements)
+	require.Equal(t, SecurityScheme{Type: "apiKey", In: "cookie", Name: "AuthKey"}, doc.Components.SecuritySchemes["api_key"])
+
+	// repeated legacy definition
+	_, err = parser.parseSecurity("@openapiSecurity api_key apiKey cookie AuthKey")
+	require.Nil(t, err)
+
+	// AND / OR with scopes
+	requirements, err = parser.parseSecurity("@openapiSecurity bearer | oauth[read:users, write:users] api_key")
+	require.Nil(t, err)
+	require.Equal(t, SecurityRequirements{
+		{"bearer": {}},
+		{"oauth": {"read:users", "write:users"}, "api_key": {}},
+	}, requirements)
+
+	// public endpoint
+	requirements, err = parser.parseSecurity("@openapiSecurity none")
+	require.Nil(t, err)
+	require.NotNil(t, requirements)
+	require.Equal(t, 0, len(requirements))
+
+	_, err = parser.parseSecurity("@openapiSecurity bearer | none")
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid security requirement", err.Error())
+
+	_, err = parser.parseSecurity("@openapiSecurity oauth[read")
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid security scopes", err.Error())
+}
+
+func TestParseSecurityScheme(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	err := parser.parseSecurityScheme("@openapiSecurityScheme bearer http scheme=bearer, bearerFormat=JWT, description=JWT token")
+	require.Nil(t, err)
+	require.Equal(t, SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "JWT token"}, doc.Components.SecuritySchemes["bearer"])
+
+	err = parser.parseSecurityScheme("@openapiSecurityScheme key apiKey in=header, name=X-API-Key")
+	require.Nil(t, err)
+	require.Equal(t, SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}, doc.Components.SecuritySchemes["key"])
+
+	err = parser.parseSecurityScheme("@openapiSecurityScheme oidc openIdConnect url=https://example.com/.well-known/openid-configuration")
+	require.Nil(t, err)
+	require.Equal(t, "https://example.com/.well-known/openid-configuration", doc.Components.SecuritySchemes["oidc"].OpenIDConnectURL)
+
+	// oauth2 flows are merged
+	err = parser.parseSecurityScheme("@openapiSecurityScheme oauth oauth2 flow=authorizationCode, authorizationUrl=https://example.com/authorize, tokenUrl=https://example.com/token?a=b, scopes={'read:users': 'Read users', 'write:users': 'Modify users'}")
+	require.Nil(t, err)
+	err = parser.parseSecurityScheme("@openapiSecurityScheme oauth oauth2 flow=clientCredentials, tokenUrl=https://example.com/token, scopes=admin")
+	require.Nil(t, err)
+
+	flows := doc.Components.SecuritySchemes["oauth"].Flows
+	require.NotNil(t, flows)
+	require.Equal(t, "https://example.com/token?a=b", flows.AuthorizationCode.TokenURL)
+	require.Equal(t, map[string]string{"read:users": "Read users", "write:users": "Modify users"}, flows.AuthorizationCode.Scopes)
+	require.Equal(t, map[string]string{"admin": ""}, flows.ClientCredentials.Scopes)
+	require.Nil(t, flows.Implicit)
+
+	// conflicts
+	err = parser.parseSecurityScheme("@openapiSecurityScheme bearer http scheme=basic")
+	require.NotNil(t, err)
+	require.Equal(t, "security scheme 'bearer' already defined", err.Error())
+
+	// invalid schemes
+	err = parser.parseSecurityScheme("@openapiSecurityScheme foo apiKey in=body, name=foo")
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid security scheme 'in'", err.Error())
+
+	err = parser.parseSecurityScheme("@openapiSecurityScheme foo oauth2 flow=implicit, tokenUrl=https://example.com/token")
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid OAuth flow 'authorizationUrl'", err.Error())
+
+	err = parser.parseSecurityScheme("@openapiSecurityScheme foo basic")
+	require.NotNil(t, err)
+	require.Equal(t, "Invalid security scheme type", err.Error())
+}
+
+func TestValidateDoc(t *testing.T) {
+	doc := &Doc{
+		Paths:      map[string]Path{},
+		Components: Component{Schemas: map[string]*Schema{}},
+	}
+	parser := NewParser(doc, newStructsParser())
+
+	err := parser.parseComment(`
+@openapiSecurityScheme bearer http scheme=bearer
+@openapiSecurityScheme oauth oauth2 flow=clientCredentials, tokenUrl=https://example.com/token, scopes=read
+@openapiDefaultSecurity bearer
+`, File{})
+	require.Nil(t, err)
+	require.Equal(t, SecurityRequirements{{"bearer": {}}}, doc.Security)
+
+	err = parser.parseComment(`
+@openapi GET /public
+@openapiSecurity none
+@openapiResponse 200 application/json {}
+`, File{})
+	require.Nil(t, err)
+	require.NotNil(t, doc.Paths["/public"]["get"].Security)
+
+	err = parser.parseComment(`
+@openapi GET /private
+@openapiSecurity oauth[read write] bearer[read]
+@openapiSecurity unknown
+@openapiResponse 200 application/json {}
+`, File{})
+	require.Nil(t, err)
+
+	errors := validateDoc(doc)
+	require.Equal(t, 3, len(errors))
+	require.Equal(t, "security scheme 'bearer' does not support scopes (GET /private)", errors[0].Error())
+	require.Equal(t, "unknown scope 'write' for security scheme 'oauth' (GET /private)", errors[1].Error())
+	require.Equal(t, "unknown security scheme 'unknown' (GET /private)", errors[2].Error())
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	problemSchemaName  = "Problem"
+	problemContentType = "application/problem+json"
+	// problemImportPath marks built-in Problem schema in components
+	problemImportPath = "rfc7807"
+)
+
+// newProblemSchema returns RFC 7807 problem details schema
+func newProblemSchema() *Schema {
+	return &Schema{
+		importPath:  problemImportPath,
+		Type:        "object",
+		Description: "Problem details (RFC 7807)",
+		Properties: map[string]*Schema{
+			"type": {
+				Type:        "string",
+				Format:      "uri-reference",
+				Default:     "about:blank",
+				Description: "URI reference that identifies the problem type",
+			},
+			"title": {
+				Type:        "string",
+				Description: "Short, human-readable summary of the problem type",
+			},
+			"status": {
+				Type:        "integer",
+				Minimum:     ptr(Number(100)),
+				Maximum:     ptr(Number(599)),
+				Description: "HTTP status code",
+			},
+			"detail": {
+				Type:        "string",
+				Description: "Human-readable explanation specific to this occurrence of the problem",
+			},
+			"instance": {
+				Type:        "string",
+				Format:      "uri-reference",
+				Description: "URI reference that identifies the specific occurrence of the problem",
+			},
+		},
+	}
+}
+
+// parseProblem @openapiProblem 404
+//
+//	@openapiProblem 404 "User not found"
+//	@openapiProblem 404 User not found
+//	@openapiProblem 422 "Invalid user" type=ValidationProblem
+//	Problem schema is extended with given struct via allOf.
+func (p *Parser) parseProblem(s string, file File) (status string, response Response, err error) {
+	var ok bool
+	s = trim(strings.TrimPrefix(s, problemPrefix))
+	status, s, _ = strings.Cut(s, " ")
+	status = normalizeStatus(status)
+
+	response.Description, s, ok = cutQuoted(trim(s))
+	if !ok {
+		return status, response, fmt.Errorf("Invalid response description")
+	}
+
+	// extension type is set by explicit key, unquoted text is description
+	description, extensionType, _ := strings.Cut(" "+s, " type=")
+	if description = trim(description); description != "" {
+		if response.Description != "" {
+			return status, response, fmt.Errorf("Invalid problem extension")
+		}
+		response.Description = description
+	}
+	if response.Description == "" {
+		response.Description = statusDescription(status)
+	}
+
+	problem, err := p.problemSchema()
+	if err != nil {
+		return status, response, err
+	}
+
+	content := Content{
+		Schema: problem,
+	}
+	if extensionType = trim(extensionType); extensionType != "" {
+		extension, err := p.parseSchema(extensionType, file)
+		if err != nil {
+			return status, response, err
+		}
+		if extension.Schema == nil {
+			return status, response, fmt.Errorf("Invalid problem extension")
+		}
+
+		content.Schema = &Schema{
+			AllOf: []*Schema{problem, extension.Schema},
+		}
+	}
+
+	response.Content = map[string]Content{
+		problemContentType: content,
+	}
+
+	return status, response, validateResponse(status, response)
+}
+
+// problemSchema adds built-in Problem schema to components and returns reference to it
+func (p *Parser) problemSchema() (*Schema, error) {
+	existing, ok := p.doc.Components.Schemas[problemSchemaName]
+	if !ok {
+		p.doc.Components.Schemas[problemSchemaName] = newProblemSchema()
+	} else if existing.importPath != problemImportPath {
+		return nil, fmt.Errorf("schema '%s' already defined", problemSchemaName)
+	}
+
+	return &Schema{
+		Ref: fmt.Sprintf("#/components/schemas/%s", problemSchemaName),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ruleFormats formats of string validator rules
+	ruleFormats = map[string]string{
+		"email":            "email",
+		"url":              "uri",
+		"uri":              "uri",
+		"http_url":         "uri",
+		"uuid":             "uuid",
+		"uuid4":            "uuid",
+		"uuid5":            "uuid",
+		"ipv4":             "ipv4",
+		"ipv6":             "ipv6",
+		"hostname":         "hostname",
+		"hostname_rfc1123": "hostname",
+	}
+	// rulePatterns patterns of string validator rules
+	rulePatterns = map[string]string{
+		"alpha":       "^[a-zA-Z]+$",
+		"alphanum":    "^[a-zA-Z0-9]+$",
+		"numeric":     `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+		"number":      "^[0-9]+$",
+		"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+		"e164":        `^\+[1-9]?[0-9]{7,14}$`,
+	}
+	// oneofRegexp matches values of oneof rule, values with spaces are quoted by single quotes
+	oneofRegexp = regexp.MustCompile(`'[^']*'|\S+`)
+)
+
+// validateRules returns rules of validate and binding tags enabled by options
+func (p *Parser) validateRules(tags Tags) []string {
+	rules := []string{}
+	if p.options.ValidateTag {
+		rules = append(rules, splitRules(tags.Validate)...)
+	}
+	if p.options.BindingTag {
+		rules = append(rules, splitRules(tags.Binding)...)
+	}
+
+	return rules
+}
+
+// isRequired checks openapi:"required" and required rule of enabled validation tags
+func (p *Parser) isRequired(tags Tags) bool {
+	if _, ok := tags.Openapi["required"]; ok {
+		return true
+	}
+
+	return strIn("required", p.validateRules(tags))
+}
+
+// splitRules required,min=3,dive,email
+//
+//	Rules after dive are applied to elements and skipped.
+func splitRules(tag string) []string {
+	rules := []string{}
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "dive" || rule == "keys" {
+			break
+		}
+		// or-rules can't be described by constraints
+		if rule == "" || strings.Contains(rule, "|") {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// applyRules sets constraints of validator rules to schema.
+//
+//	Rules with params not matching schema type are skipped.
+func applyRules(schema *Schema, rules []string) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch schema.Type {
+		case "string":
+			applyLengthRule(&schema.MinLength, &schema.MaxLength, name, param)
+			if format, ok := ruleFormats[name]; ok {
+				schema.Format = format
+			}
+			if pattern, ok := rulePatterns[name]; ok {
+				schema.Pattern = pattern
+			}
+
+		case "array":
+			applyLengthRule(&schema.MinItems, &schema.MaxItems, name, param)
+
+		case "integer", "number":
+			applyRangeRule(schema, name, param)
+		}
+
+		if name == "oneof" && param != "" {
+			values := oneofRegexp.FindAllString(param, -1)
+			for i := range values {
+				values[i] = strings.Trim(values[i], "'")
+			}
+			schema.setEnum(values)
+		}
+	}
+}
+
+// applyLengthRule sets min and max length of strings and arrays
+func applyLengthRule(min, max **int, name, param string) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	switch name {
+	case "min", "gte":
+		*min = ptr(n)
+	case "gt":
+		*min = ptr(n + 1)
+	case "max", "lte":
+		*max = ptr(n)
+	case "lt":
+		*max = ptr(n - 1)
+	case "len":
+		*min = ptr(n)
+		*max = ptr(n)
+	}
+}
+
+// applyRangeRule sets minimum and maximum of numbers
+func applyRangeRule(schema *Schema, name, param string) {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	n := Number(f)
+
+	switch name {
+	case "min", "gte":
+		schema.Minimum = ptr(n)
+	case "gt":
+		schema.Minimum = ptr(n)
+		schema.ExclusiveMinimum = true
+	case "max", "lte":
+		schema.Maximum = ptr(n)
+	case "lt":
+		schema.Maximum = ptr(n)
+		schema.ExclusiveMaximum = true
+	case "eq", "len":
+		schema.Minimum = ptr(n)
+		schema.Maximum = ptr(n)
+	}
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"log"
 	"reflect"
 	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
 const (
@@ -20,18 +25,51 @@ type StructField struct {
 	IsExported bool
 	IsPointer  bool
 	IsSystem   bool
+	// IsEmbedded field is anonymous, it is named by its type
+	IsEmbedded bool
+	// Doc doc or line comment of field
+	Doc string
 }
 
 type Struct struct {
 	Pkg    string
 	Name   string
 	Origin string
+	Doc    string
 	Fields []StructField
+	// IsAnonymous struct is type of field, it is inlined to schema of field
+	IsAnonymous bool
+	// TypeParams names of type parameters of generic type
+	TypeParams []string
+}
+
+// Value is package level constant or variable
+type Value struct {
+	Name    string
+	IsConst bool
+	// Type declared type of variable
+	Type  ast.Expr
+	Value ast.Expr
+	// Const exact value of constant computed by go/types
+	Const constant.Value
+	// ConstType type of constant, nil for untyped constant
+	ConstType *types.Named
+	// Pkg package where value is declared
+	Pkg *types.Package
+	Doc string
+	// Pos position of declaration, values of package are ordered by it
+	Pos  token.Pos
+	File *ast.File
+	// Info types of expressions of File
+	Info *types.Info
 }
 
 func newStructsParser() *structsParser {
 	return &structsParser{
-		structs: map[string]map[string]Struct{},
+		structs:    map[string]map[string]Struct{},
+		values:     map[string]map[string]Value{},
+		mainModule: map[string]bool{},
+		marshalers: map[string]map[string]bool{},
 	}
 }
 
@@ -39,6 +77,12 @@ type structsParser struct {
 	// importName -> local struct type name -> struct definition
 	// fo example "github.com/onrik/gaws" -> "Type" -> Struct{}
 	structs map[string]map[string]Struct
+	// importName -> constant or variable name -> definition
+	values map[string]map[string]Value
+	// importName -> package belongs to main module, not to GOROOT or dependencies
+	mainModule map[string]bool
+	// importName -> names of types which implement encoding.TextMarshaler
+	marshalers map[string]map[string]bool
 }
 
 // parse parses structs from given go package
@@ -50,6 +94,7 @@ func (p *structsParser) parse(pkg Package) (map[string]Struct, error) {
 
 	resp := map[string]Struct{}
 	p.structs[pkg.ImportPath] = resp
+	p.marshalers[pkg.ImportPath] = map[string]bool{}
 
 	pkgs, err := parser.ParseDir(token.NewFileSet(), pkg.FSPath, nil, parser.ParseComments)
 	if err != nil {
@@ -62,28 +107,164 @@ func (p *structsParser) parse(pkg Package) (map[string]Struct, error) {
 				continue
 			}
 			ast.Inspect(f, p.inspectFile(pkg.ImportPath))
+			p.inspectMethods(pkg.ImportPath, f)
 		}
 	}
 
 	return resp, nil
 }
 
+// parseValues parses package level constants and variables from given go package.
+//
+//	Package is type checked, so values of constants are exact and expressions of variables have types.
+func (p *structsParser) parseValues(pkg Package) (map[string]Value, error) {
+	if resp, ok := p.values[pkg.ImportPath]; ok {
+		return resp, nil
+	}
+
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax | packages.NeedModule,
+		Dir:  pkg.FSPath,
+	}
+	pkgList, err := packages.Load(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgList) == 0 || pkgList[0].Types == nil {
+		return nil, fmt.Errorf("package '%s' was not loaded", pkg.FSPath)
+	}
+
+	resp := map[string]Value{}
+	p.values[pkg.ImportPath] = resp
+	p.mainModule[pkg.ImportPath] = pkgList[0].Module != nil && pkgList[0].Module.Main
+	for _, f := range pkgList[0].Syntax {
+		inspectValues(resp, f, pkgList[0].Types, pkgList[0].TypesInfo)
+	}
+
+	return resp, nil
+}
+
+// valueDoc returns doc or line comment of constant or variable
+func valueDoc(d *ast.GenDecl, s *ast.ValueSpec) string {
+	switch {
+	case s.Doc != nil:
+		return trim(s.Doc.Text())
+	case s.Comment != nil:
+		return trim(s.Comment.Text())
+	case len(d.Specs) == 1 && d.Doc != nil:
+		return trim(d.Doc.Text())
+	default:
+		return ""
+	}
+}
+
+// isTextMarshaler reports whether value of type of given go package implements encoding.TextMarshaler
+func (p *structsParser) isTextMarshaler(pkg Package, name string) (bool, error) {
+	if _, err := p.parse(pkg); err != nil {
+		return false, err
+	}
+
+	return p.marshalers[pkg.ImportPath][name], nil
+}
+
+func (p *structsParser) inspectMethods(importPath string, f *ast.File) {
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.FuncDecl)
+		if !ok || d.Recv == nil || len(d.Recv.List) == 0 || d.Name.Name != "MarshalText" {
+			continue
+		}
+
+		// encoding/json uses MarshalText of map key only if value of key type implements it
+		if _, ok := d.Recv.List[0].Type.(*ast.StarExpr); ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(getType(d.Recv.List[0].Type), "[")
+		p.marshalers[importPath][name] = true
+	}
+}
+
+func inspectValues(values map[string]Value, f *ast.File, pkg *types.Package, info *types.Info) {
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || (d.Tok != token.CONST && d.Tok != token.VAR) {
+			continue
+		}
+
+		// constants without values repeat previous type and values
+		var (
+			typ   ast.Expr
+			exprs []ast.Expr
+		)
+		for _, spec := range d.Specs {
+			s := spec.(*ast.ValueSpec)
+			if d.Tok == token.VAR || len(s.Values) > 0 {
+				typ = s.Type
+				exprs = s.Values
+			}
+
+			for j, name := range s.Names {
+				if name.Name == "_" {
+					continue
+				}
+
+				v := Value{
+					Name:    name.Name,
+					IsConst: d.Tok == token.CONST,
+					Type:    typ,
+					Doc:     valueDoc(d, s),
+					Pos:     name.Pos(),
+					File:    f,
+					Pkg:     pkg,
+					Info:    info,
+				}
+				if j < len(exprs) {
+					v.Value = exprs[j]
+				}
+				if c, ok := info.Defs[name].(*types.Const); ok {
+					v.Const = c.Val()
+					v.ConstType, _ = c.Type().(*types.Named)
+				}
+				values[name.Name] = v
+			}
+		}
+	}
+}
+
 func (p *structsParser) inspectFile(importPath string) func(node ast.Node) bool {
+	var decl *ast.GenDecl
 	return func(node ast.Node) bool {
+		if d, ok := node.(*ast.GenDecl); ok {
+			decl = d
+			return true
+		}
+
 		t, ok := node.(*ast.TypeSpec)
 		if !ok {
 			return true
 		}
-		// skip unexported structs from underlying packages
-		if !t.Name.IsExported() && importPath != "" {
-			return true
+
+		// doc of single type declaration belongs to declaration
+		doc := t.Doc
+		if doc == nil && decl != nil && len(decl.Specs) == 1 && decl.Specs[0] == t {
+			doc = decl.Doc
+		}
+		var typeParams []string
+		if t.TypeParams != nil {
+			for _, param := range t.TypeParams.List {
+				for _, name := range param.Names {
+					typeParams = append(typeParams, name.Name)
+				}
+			}
 		}
 
 		if alias := checkIsAlias(t.Type); alias != "" {
 			p.structs[importPath][t.Name.Name] = Struct{
-				Pkg:    importPath,
-				Name:   t.Name.Name,
-				Origin: alias,
+				Pkg:        importPath,
+				Name:       t.Name.Name,
+				Origin:     alias,
+				Doc:        doc.Text(),
+				TypeParams: typeParams,
 			}
 			return true
 		}
@@ -93,41 +274,102 @@ func (p *structsParser) inspectFile(importPath string) func(node ast.Node) bool
 			return true
 		}
 
-		fields := []StructField{}
-		for _, field := range s.Fields.List {
-			f := StructField{}
-			if len(field.Names) > 0 {
-				f.Name = field.Names[0].Name
-				f.IsExported = field.Names[0].IsExported()
-				f.IsSystem = f.Name == systemFieldName
-			}
+		p.structs[importPath][t.Name.Name] = Struct{
+			Pkg:        importPath,
+			Name:       t.Name.Name,
+			Doc:        doc.Text(),
+			Fields:     p.parseFields(importPath, t.Name.Name, s),
+			TypeParams: typeParams,
+		}
 
-			if field.Tag != nil {
-				f.Tag = field.Tag.Value
-			}
+		return true
+	}
+}
 
-			if !f.IsSystem {
-				if !f.IsExported {
-					continue
-				}
+// parseFields parses fields of struct with given name
+func (p *structsParser) parseFields(importPath, name string, s *ast.StructType) []StructField {
+	fields := []StructField{}
+	for _, field := range s.Fields.List {
+		f := StructField{}
+		if len(field.Names) > 0 {
+			f.Name = field.Names[0].Name
+			f.IsExported = field.Names[0].IsExported()
+			f.IsSystem = f.Name == systemFieldName
+		} else {
+			// embedded generic struct is named without type arguments
+			name, _, _ := strings.Cut(strings.TrimPrefix(getType(field.Type), "*"), "[")
+			_, f.Name = splitName(name)
+			f.IsExported = ast.IsExported(f.Name)
+			f.IsEmbedded = true
+		}
 
-				f.Type = getType(field.Type)
-				f.IsPointer = strings.HasPrefix(f.Type, "*")
-				if f.Type == "" {
-					continue
+		if field.Tag != nil {
+			f.Tag = field.Tag.Value
+		}
+
+		if field.Doc != nil {
+			f.Doc = trim(field.Doc.Text())
+		} else if field.Comment != nil {
+			f.Doc = trim(field.Comment.Text())
+		}
+
+		if !f.IsSystem {
+			// fields of unexported embedded structs are promoted
+			if !f.IsExported && !f.IsEmbedded {
+				continue
+			}
+
+			f.Type = p.fieldType(importPath, name+"{"+f.Name+"}", field.Type)
+			f.IsPointer = strings.HasPrefix(f.Type, "*")
+			if f.Type == "" {
+				if getTag(f.Tag, "json") != "-" {
+					log.Printf("Unsupported type of field '%s' in struct '%s' is skipped\n", f.Name, name)
 				}
+				continue
 			}
+		}
+
+		fields = append(fields, f)
+	}
 
-			fields = append(fields, f)
+	return fields
+}
+
+// fieldType returns type of struct field.
+//
+//	Anonymous struct is added to structs with given name, for example 'User{Meta}' for field Meta of struct User.
+func (p *structsParser) fieldType(importPath, name string, e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.StructType:
+		p.structs[importPath][name] = Struct{
+			Pkg:         importPath,
+			Name:        name,
+			IsAnonymous: true,
+			Fields:      p.parseFields(importPath, name, t),
 		}
+		return name
 
-		p.structs[importPath][t.Name.Name] = Struct{
-			Pkg:    importPath,
-			Name:   t.Name.Name,
-			Fields: fields,
+	case *ast.ArrayType:
+		if elt := p.fieldType(importPath, name, t.Elt); elt != "" {
+			return "[]" + elt
+		}
+		return ""
+
+	case *ast.StarExpr:
+		if x := p.fieldType(importPath, name, t.X); x != "" {
+			return "*" + x
 		}
+		return ""
 
-		return true
+	case *ast.MapType:
+		key, value := getType(t.Key), p.fieldType(importPath, name, t.Value)
+		if key == "" || value == "" {
+			return ""
+		}
+		return "map[" + key + "]" + value
+
+	default:
+		return getType(e)
 	}
 }
 
@@ -142,9 +384,22 @@ func getType(e ast.Expr) string {
 	case *ast.StarExpr:
 		return "*" + getType(t.X)
 	case *ast.MapType:
-		return "map"
-	case *ast.FuncType, *ast.ChanType, *ast.StructType, *ast.InterfaceType:
-		// TODO
+		key, value := getType(t.Key), getType(t.Value)
+		if key == "" || value == "" {
+			return ""
+		}
+		return "map[" + key + "]" + value
+	case *ast.IndexExpr:
+		return getType(t.X) + "[" + getType(t.Index) + "]"
+	case *ast.IndexListExpr:
+		indices := make([]string, len(t.Indices))
+		for i := range t.Indices {
+			indices[i] = getType(t.Indices[i])
+		}
+		return getType(t.X) + "[" + strings.Join(indices, ",") + "]"
+	case *ast.InterfaceType:
+		return "interface{}"
+	case *ast.FuncType, *ast.ChanType, *ast.StructType:
 		return ""
 	default:
 		log.Println("Unsupported type:", reflect.TypeOf(t))
@@ -154,7 +409,7 @@ func getType(e ast.Expr) string {
 
 func checkIsAlias(e ast.Expr) string {
 	switch e.(type) {
-	case *ast.SelectorExpr, *ast.ArrayType, *ast.Ident:
+	case *ast.SelectorExpr, *ast.ArrayType, *ast.Ident, *ast.InterfaceType, *ast.MapType, *ast.IndexExpr, *ast.IndexListExpr:
 		return getType(e)
 	default:
 		return ""
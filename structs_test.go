@@ -13,7 +13,7 @@ func TestParseStructs(t *testing.T) {
 	st, err := p.parse(Package{FSPath: "./tests/", ImportPath: ""})
 	require.NoError(t, err)
 	require.Nil(t, err)
-	require.Equal(t, 15, len(st))
+	require.Equal(t, 74, len(st))
 
 	s, ok := st["User"]
 	require.True(t, ok)
@@ -47,6 +47,48 @@ func TestParseStructs(t *testing.T) {
 	require.Equal(t, "Data", s.Fields[1].Name)
 	require.Equal(t, "json3.RawMessage", s.Fields[1].Type)
 
+	// embedded structs are named by type
+	s, ok = st["Admin"]
+	require.True(t, ok)
+	require.Equal(t, 5, len(s.Fields))
+	require.Equal(t, "Model", s.Fields[0].Name)
+	require.True(t, s.Fields[0].IsEmbedded)
+	require.True(t, s.Fields[0].IsExported)
+	require.Equal(t, "auditInfo", s.Fields[1].Name)
+	require.Equal(t, "*auditInfo", s.Fields[1].Type)
+	require.False(t, s.Fields[1].IsExported)
+	require.Equal(t, "NestedStruct", s.Fields[2].Name)
+	require.Equal(t, "nested.NestedStruct", s.Fields[2].Type)
+
+	// anonymous structs are named by parent struct and field
+	s, ok = st["Envelope"]
+	require.True(t, ok)
+	require.Equal(t, 5, len(s.Fields))
+	require.Equal(t, "Envelope{Meta}", s.Fields[0].Type)
+	require.Equal(t, "any", s.Fields[1].Type)
+	require.Equal(t, "interface{}", s.Fields[2].Type)
+	require.Equal(t, "[]Envelope{Meta}{Links}", st["Envelope{Meta}"].Fields[1].Type)
+	require.True(t, st["Envelope{Meta}{Links}"].IsAnonymous)
+
+	// map types keep key and value types
+	s, ok = st["Catalog"]
+	require.True(t, ok)
+	require.Equal(t, "map[string]Item", s.Fields[0].Type)
+	require.Equal(t, "map[int64]*Item", s.Fields[2].Type)
+	require.Equal(t, "map[string]map[string]bool", s.Fields[6].Type)
+	require.Equal(t, "map[string]Catalog{Meta}", s.Fields[7].Type)
+	require.Equal(t, "map[string]int", st["Counters"].Origin)
+
+	// generic structs
+	s, ok = st["Result"]
+	require.True(t, ok)
+	require.Equal(t, []string{"T", "E"}, s.TypeParams)
+	require.Equal(t, "Page[T]", s.Fields[2].Type)
+	require.Equal(t, "[]*Node[T]", st["Node"].Fields[1].Type)
+	require.Equal(t, "Page", st["Paged"].Fields[0].Name)
+	require.Equal(t, "[]T", st["List"].Origin)
+	require.Equal(t, "Page[nested.NestedStruct]", st["NestedPage"].Origin)
+
 	// alias structs
 	s, ok = st["SliceAlias"]
 	require.True(t, ok)
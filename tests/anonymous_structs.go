@@ -0,0 +1,18 @@
+package tests
+
+type Payload interface{}
+
+type Envelope struct {
+	Meta struct {
+		Page  int `json:"page"`
+		Links []struct {
+			Href string `json:"href"`
+		} `json:"links"`
+	} `json:"meta"`
+	Payload  any         `json:"payload"`
+	Extra    interface{} `json:"extra,omitempty"`
+	Items    []any       `json:"items"`
+	Raw      Payload     `json:"raw"`
+	Callback func()      `json:"-"`
+	Updates  chan string
+}
@@ -4,3 +4,30 @@ type DescriptionStruct struct {
 	_  struct{} `json:"-" openapiDesc:"Test description"`
 	ID int
 }
+
+// DocStruct is described by doc comment.
+//
+// @openapiExample exampleDocStruct
+type DocStruct struct {
+	// ID is identifier
+	// of struct
+	ID   int
+	Name string // name of struct
+	// Title is overridden by tag
+	Title string `openapiDesc:"Tag title"`
+}
+
+// TaggedDocStruct is overridden by system field
+type TaggedDocStruct struct {
+	_  struct{} `openapiDesc:"Tag description"`
+	ID int
+}
+
+var exampleDocStruct = DocStruct{ID: 1}
+
+type RefDocStruct struct {
+	// Item is described reference
+	Item  DescriptionStruct
+	Plain DescriptionStruct
+	Ext   DescriptionStruct `openapiExt:"x-order=1"`
+}
@@ -0,0 +1,42 @@
+package tests
+
+import "github.com/onrik/gaws/tests/nested"
+
+type Model struct {
+	ID        int    `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type auditInfo struct {
+	UpdatedBy string `json:"updated_by"`
+	Name      string `json:"name"`
+}
+
+type Admin struct {
+	Model
+	*auditInfo
+	nested.NestedStruct `json:"nested"`
+	Name                string `json:"name"`
+	Level               int    `json:"level,omitempty"`
+}
+
+type Conflict struct {
+	Left
+	Right
+}
+
+type Left struct {
+	Name string
+	ID   int `json:"ID"`
+}
+
+type Right struct {
+	Name string
+	ID   int
+}
+
+var exampleAdmin = Admin{
+	Model:     Model{ID: 1},
+	auditInfo: &auditInfo{UpdatedBy: "root", Name: "shadowed"},
+	Name:      "John",
+}
@@ -0,0 +1,34 @@
+package tests
+
+import "time"
+
+type OrderStatus string
+
+const (
+	// OrderNew is created order
+	OrderNew      OrderStatus = "new"
+	OrderPaid     OrderStatus = "paid" // paid by customer
+	OrderCanceled OrderStatus = "canceled"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+	priorityHidden
+)
+
+// PriorityUrgent is typed by type checker
+const PriorityUrgent = PriorityHigh * 2
+
+const defaultPriority = PriorityLow
+
+type Order struct {
+	Status   OrderStatus   `json:"status"`
+	History  []OrderStatus `json:"history"`
+	Priority Priority      `json:"priority"`
+	Legacy   OrderStatus   `json:"legacy" openapiEnum:"-"`
+	Custom   OrderStatus   `json:"custom" openapiEnum:"new,paid"`
+	Timeout  time.Duration `json:"timeout"`
+}
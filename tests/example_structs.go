@@ -0,0 +1,48 @@
+package tests
+
+type Role int
+
+const (
+	RoleUser Role = iota + 1
+	RoleAdmin
+)
+
+const defaultEmail = "john@example.com"
+
+// Account is user account
+//
+// @openapiExample exampleAccount
+type Account struct {
+	ID       int64          `json:"id"`
+	Email    string         `json:"email"`
+	Role     Role           `json:"role"`
+	Nickname string         `json:"nickname,omitempty"`
+	Status   UserStatus     `json:"status"`
+	Tags     []string       `json:"tags"`
+	Limits   map[string]int `json:"limits,omitempty"`
+	Owner    *AccountOwner  `json:"owner,omitempty"`
+	Balance  float64        `json:"balance"`
+	Internal string         `json:"-"`
+}
+
+type AccountOwner struct {
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+var exampleAccount = Account{
+	ID:       1 << 10,
+	Email:    defaultEmail,
+	Role:     RoleAdmin,
+	Status:   UserStatus("new"),
+	Tags:     []string{"a", "b"},
+	Limits:   map[string]int{"requests": 100},
+	Owner:    &AccountOwner{Name: "John"},
+	Balance:  -0.5,
+	Internal: "secret",
+}
+
+var exampleAccounts = []Account{
+	exampleAccount,
+	{ID: 2, Email: "jane@example.com", Role: RoleUser},
+}
@@ -0,0 +1,20 @@
+package tests
+
+import (
+	"mime/multipart"
+	upload "mime/multipart"
+)
+
+type UploadForm struct {
+	Name    string                  `form:"name" openapi:"required"`
+	Avatar  *multipart.FileHeader   `form:"avatar" openapiEncoding:"contentType=image/png image/jpeg, headers={'X-Checksum': 'string'}"`
+	Files   []*multipart.FileHeader `form:"files"`
+	Data    []byte                  `form:"data"`
+	Tags    []string                `form:"tags" openapiEncoding:"style=form, explode"`
+	Ignored string                  `form:"-"`
+	Note    string
+}
+
+type AliasedUploadForm struct {
+	File *upload.FileHeader `form:"file"`
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"github.com/onrik/gaws/tests/nested"
+	n2 "github.com/onrik/gaws/tests/nested/nested"
+)
+
+type Page[T any] struct {
+	Items []T  `json:"items"`
+	Total int  `json:"total"`
+	Next  *T   `json:"next,omitempty"`
+	Meta  Meta `json:"meta"`
+}
+
+type Meta struct {
+	Cursor string `json:"cursor"`
+}
+
+type Result[T any, E any] struct {
+	Data   T            `json:"data"`
+	Errors []E          `json:"errors"`
+	Pages  Page[T]      `json:"pages"`
+	Index  map[string]T `json:"index"`
+}
+
+type Node[T any] struct {
+	Value    T          `json:"value"`
+	Children []*Node[T] `json:"children"`
+}
+
+type Paged[T any] struct {
+	Page[T]
+	Cursor string `json:"cursor"`
+}
+
+type List[T any] []T
+
+type UserPage = Page[Meta]
+
+type NestedPage = Page[nested.NestedStruct]
+
+type DeepNestedPage = Page[n2.NestedStruct]
+
+var examplePage = Page[Meta]{
+	Items: []Meta{{Cursor: "a"}},
+	Total: 1,
+}
+
+/*
+@openapi GET /pages
+@openapiResponse 200 application/json Result[nested.NestedStruct,string]
+*/
+func Pages() {}
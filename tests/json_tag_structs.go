@@ -0,0 +1,38 @@
+package tests
+
+type base struct {
+	ID int `json:"id"`
+}
+
+type secret string
+
+type labeled struct {
+	Label string `json:"label"`
+}
+
+type Point struct {
+	X int `json:"x"`
+}
+
+// @openapiExample exampleJSONOptions
+type JSONOptions struct {
+	base
+	secret
+	labeled  `json:"labeled"`
+	hidden   string
+	Count    int64    `json:"count,string"`
+	Ratio    float64  `json:"ratio,string,omitempty"`
+	Enabled  *bool    `json:"enabled,string"`
+	Title    string   `json:"title,string"`
+	Level    int      `json:"level,string" openapi:"default=1"`
+	Dash     string   `json:"-,"`
+	Invalid  string   `json:"a\\b"`
+	Name     string   `json:"Name"`
+	LowName  string   `json:"name"`
+	Point    Point    `json:"point,omitzero"`
+	Empty    []string `json:"empty,omitzero"`
+	Nil      []string `json:"nil,omitzero"`
+	Optional string   `json:"optional,omitzero"`
+}
+
+var exampleJSONOptions = JSONOptions{Count: 5, Title: "t", Empty: []string{}}
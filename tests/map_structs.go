@@ -0,0 +1,57 @@
+package tests
+
+import "time"
+
+type Key struct {
+	Group string
+	Name  string
+}
+
+func (k Key) MarshalText() ([]byte, error) {
+	return []byte(k.Group + ":" + k.Name), nil
+}
+
+type Counters map[string]int
+
+type Item struct {
+	Name string `json:"name"`
+}
+
+type Catalog struct {
+	Users  map[string]Item            `json:"users"`
+	Scores map[string][]int           `json:"scores"`
+	ByID   map[int64]*Item            `json:"by_id"`
+	ByCode map[uint]string            `json:"by_code"`
+	ByTime map[time.Time]string       `json:"by_time"`
+	ByKey  map[Key]string             `json:"by_key"`
+	Nested map[string]map[string]bool `json:"nested"`
+	Meta   map[string]struct {
+		Note string `json:"note"`
+	} `json:"meta"`
+	Free     map[string]any `json:"free"`
+	Counters Counters       `json:"counters"`
+}
+
+type InvalidKeyMap struct {
+	ByModel map[Model]string `json:"by_model"`
+}
+
+type PtrKey struct {
+	Name string
+}
+
+func (k *PtrKey) MarshalText() ([]byte, error) {
+	return []byte(k.Name), nil
+}
+
+type PtrKeyMap struct {
+	ByKey map[PtrKey]string `json:"by_key"`
+}
+
+type FloatKeyMap struct {
+	ByRate map[float64]string `json:"by_rate"`
+}
+
+type BoolKeyMap struct {
+	ByFlag map[bool]string `json:"by_flag"`
+}
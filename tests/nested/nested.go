@@ -8,3 +8,13 @@ type NestedStruct struct {
 }
 
 type NestedSimpleAlias string
+
+type base struct {
+	ID int `json:"id"`
+}
+
+// WithBase promotes fields of unexported embedded struct
+type WithBase struct {
+	base
+	Name string `json:"name"`
+}
@@ -0,0 +1,29 @@
+package tests
+
+type Avatar struct {
+	URL string `json:"url"`
+}
+
+type Profile struct {
+	Name     string   `json:"name"`
+	Nickname *string  `json:"nickname"`
+	Bio      string   `json:"bio,omitempty"`
+	Email    string   `json:"email,omitempty" openapi:"required"`
+	Avatar   *Avatar  `json:"avatar" openapiDesc:"Profile picture"`
+	Tags     []string `json:"tags"`
+}
+
+type Base struct {
+	ID int `json:"id"`
+}
+
+// PtrEmbedded fields of Base are absent when Base is nil
+type PtrEmbedded struct {
+	*Base
+	Name string `json:"name"`
+}
+
+type ValueEmbedded struct {
+	Base
+	Name string `json:"name"`
+}
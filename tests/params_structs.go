@@ -0,0 +1,18 @@
+package tests
+
+import "time"
+
+type ListUsersFilter struct {
+	Query   string     `form:"q" openapiDesc:"Search query"`
+	Status  string     `query:"status" form:"state" openapiEnum:"new,confirmed,deleted" openapi:"default=new"`
+	IDs     []int      `form:"ids" openapi:"required"`
+	From    *time.Time `form:"from"`
+	Token   string     `header:"X-Token"`
+	GroupID int        `uri:"group_id"`
+	Skipped string     `form:"-"`
+	Page    int
+}
+
+type UserStatus string
+
+type UserStatuses []UserStatus
@@ -0,0 +1,10 @@
+package tests
+
+type ValidationProblem struct {
+	Errors []string `json:"errors"`
+}
+
+// Problem collides with built-in problem schema
+type Problem struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,15 @@
+package tests
+
+type SignupRequest struct {
+	Email    string   `json:"email" validate:"required,email"`
+	Name     string   `json:"name" validate:"required,min=3,max=64,alphanum"`
+	Code     string   `json:"code" validate:"len=6,number"`
+	Role     string   `json:"role" validate:"omitempty,oneof=admin user 'super user'"`
+	Plan     string   `json:"plan" validate:"oneof=free pro" openapiEnum:"free,pro,team"`
+	Age      int      `json:"age" validate:"gte=0,lt=150"`
+	Score    float64  `json:"score" validate:"gt=0.5,max=10"`
+	Tags     []string `json:"tags" validate:"min=1,max=5,dive,min=2"`
+	Website  string   `json:"website" validate:"url|uri"`
+	Password string   `json:"password" binding:"required,min=8"`
+	Homepage string   `json:"homepage" validate:"url" openapi:"format=hostname"`
+}
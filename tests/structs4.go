@@ -8,3 +8,7 @@ type Struct4 struct {
 	ID     int
 	Nested nested.NestedStruct
 }
+
+type Struct5 struct {
+	Item nested.WithBase `json:"item"`
+}
@@ -0,0 +1,6 @@
+package uuid
+
+// UUID is not github.com/google/uuid.UUID
+type UUID struct {
+	Value string `json:"value"`
+}
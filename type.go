@@ -8,6 +8,7 @@ const (
 	mapType
 	arrayType
 	structType
+	anyType
 )
 
 // ParsedType represents parsed type of value
@@ -16,4 +17,10 @@ type ParsedType struct {
 	Name   string
 	File   File
 	Nested *ParsedType
+	// Key type of map, Nested is type of map values
+	Key *ParsedType
+	// Args type arguments of generic struct
+	Args []*ParsedType
+	// Enum values of named base type inferred from its constants
+	Enum *Enum
 }
@@ -2,18 +2,44 @@ package main
 
 import (
 	"fmt"
+	"mime"
 	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 var (
 	httpMethods = []string{"get", "head", "post", "put", "delete", "connect", "options", "trace", "patch"}
+	// bodylessMethods methods with undefined request body semantics
+	bodylessMethods = []string{"get", "head", "delete"}
 
-	paramIn    = []string{"path", "query", "header"}
+	paramIn     = []string{"path", "query", "header", "cookie"}
+	paramStyles = map[string][]string{
+		"path":   {"matrix", "label", "simple"},
+		"query":  {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+		"header": {"simple"},
+		"cookie": {"form"},
+	}
+	// paramsTags struct tags with parameter names for every parameter location
+	paramsTags = map[string][]string{
+		"path":   {"uri", "path", "param"},
+		"query":  {"query", "form"},
+		"header": {"header"},
+		"cookie": {"cookie"},
+	}
 	paramTypes = []string{"string", "integer", "number", "boolean", "object", "array"}
 	// paramFormats = []string{"float", "double", "date", "date-time", "byte", "binary", "email", "uuid", "uri", "hostname", "ipv4", "ipv6"}
 
-	requestContentTypes  = []string{"application/json", "multipart/form-data"}
-	responseContentTypes = []string{"text/plain", "application/json", "application/octet-stream"}
+	securitySchemeTypes = []string{"apiKey", "http", "oauth2", "openIdConnect"}
+	securitySchemeIn    = []string{"query", "header", "cookie"}
+
+	streamMediaTypes = []string{"text/event-stream", "application/x-ndjson", "application/jsonl", "application/json-seq", "application/stream+json"}
+
+	// headerNameRegexp matches RFC 7230 token
+	headerNameRegexp = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$")
+	// mediaTypeRegexp matches RFC 6838 media type or media range like image/* and */*
+	mediaTypeRegexp = regexp.MustCompile(`^(\*/\*|[a-zA-Z0-9][a-zA-Z0-9!#$&^_.+-]{0,126}/(\*|[a-zA-Z0-9][a-zA-Z0-9!#$&^_.+-]{0,126}))$`)
 )
 
 func validatePath(method, path string) error {
@@ -33,32 +59,214 @@ func validateParam(p Parameter) error {
 	if !strIn(p.In, paramIn) {
 		return fmt.Errorf("Invalid param 'in'")
 	}
-	if !strIn(p.Schema.Type, paramTypes) {
+	if p.Schema.Ref == "" && !strIn(p.Schema.Type, paramTypes) {
 		return fmt.Errorf("Invalid param 'type'")
 	}
+	if p.Schema.Type == "array" && p.Schema.Items == nil {
+		return fmt.Errorf("Invalid param 'items'")
+	}
+	if p.Style != "" && !strIn(p.Style, paramStyles[p.In]) {
+		return fmt.Errorf("Invalid param 'style'")
+	}
+	if p.Style == "deepObject" && p.Schema.Type != "object" {
+		return fmt.Errorf("Invalid param 'style'")
+	}
+	if (p.Style == "spaceDelimited" || p.Style == "pipeDelimited") && p.Schema.Type != "array" && p.Schema.Type != "object" {
+		return fmt.Errorf("Invalid param 'style'")
+	}
+	if (p.AllowEmptyValue || p.AllowReserved) && p.In != "query" {
+		return fmt.Errorf("Invalid param 'in'")
+	}
+
+	return nil
+}
+
+func validateMediaType(contentType string) error {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if !mediaTypeRegexp.MatchString(trim(mediaType)) {
+		return fmt.Errorf("Invalid Content-Type")
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return fmt.Errorf("Invalid Content-Type")
+	}
+
+	return nil
+}
+
+func validateHeader(name string, h Header) error {
+	if !headerNameRegexp.MatchString(name) {
+		return fmt.Errorf("Invalid header name")
+	}
+	if strings.EqualFold(name, "Content-Type") {
+		return fmt.Errorf("Content-Type header is described by media type")
+	}
+	if h.Schema != nil && h.Schema.Ref == "" && !strIn(h.Schema.Type, paramTypes) {
+		return fmt.Errorf("Invalid header 'type'")
+	}
 
 	return nil
 }
 
 func validateRequest(body RequestBody) error {
 	for c := range body.Content {
-		if !strIn(c, requestContentTypes) {
-			return fmt.Errorf("Unsupported Content-Type")
+		if err := validateMediaType(c); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func validateResponse(status, contentType string, content Content) error {
+func validateResponse(status string, response Response) error {
 	s := atoi(status)
-	if s < 100 || s > 526 {
+	if (s < 100 || s > 526) && status != "default" && statusRangeDescriptions[status] == "" {
 		return fmt.Errorf("Invalid HTTP status code")
 	}
+	if response.Description == "" {
+		return fmt.Errorf("Invalid response description")
+	}
+
+	for contentType := range response.Content {
+		if err := validateMediaType(contentType); err != nil {
+			return err
+		}
+	}
 
-	if !strIn(contentType, responseContentTypes) {
-		return fmt.Errorf("Unsupported Content-Type")
+	return nil
+}
+
+func validateSecurityScheme(s SecurityScheme) error {
+	switch s.Type {
+	case "apiKey":
+		if s.Name == "" {
+			return fmt.Errorf("Invalid security scheme 'name'")
+		}
+		if !strIn(s.In, securitySchemeIn) {
+			return fmt.Errorf("Invalid security scheme 'in'")
+		}
+	case "http":
+		if s.Scheme == "" {
+			return fmt.Errorf("Invalid security scheme 'scheme'")
+		}
+	case "oauth2":
+		if s.Flows == nil {
+			return fmt.Errorf("Invalid OAuth flow")
+		}
+	case "openIdConnect":
+		if _, err := url.ParseRequestURI(s.OpenIDConnectURL); err != nil {
+			return fmt.Errorf("Invalid security scheme 'url'")
+		}
+	default:
+		return fmt.Errorf("Invalid security scheme type")
 	}
 
 	return nil
 }
+
+func validateOAuthFlow(name string, f *OAuthFlow) error {
+	needAuthorizationURL := name == "implicit" || name == "authorizationCode"
+	needTokenURL := name != "implicit"
+
+	if _, err := url.ParseRequestURI(f.AuthorizationURL); needAuthorizationURL && err != nil {
+		return fmt.Errorf("Invalid OAuth flow 'authorizationUrl'")
+	}
+	if _, err := url.ParseRequestURI(f.TokenURL); needTokenURL && err != nil {
+		return fmt.Errorf("Invalid OAuth flow 'tokenUrl'")
+	}
+
+	return nil
+}
+
+// validateDoc checks references between parts of complete doc
+func validateDoc(doc *Doc) []error {
+	errors := []error{}
+	for _, err := range validateSecurityRequirements(doc, doc.Security) {
+		errors = append(errors, fmt.Errorf("%s (%s)", err.Error(), trim(defaultSecurityPrefix)))
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range httpMethods {
+			endpoint, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+
+			for _, err := range validateSecurityRequirements(doc, endpoint.Security) {
+				errors = append(errors, fmt.Errorf("%s (%s %s)", err.Error(), upper(method), path))
+			}
+
+			for _, err := range validateHeaderRefs(doc, endpoint.Responses) {
+				errors = append(errors, fmt.Errorf("%s (%s %s)", err.Error(), upper(method), path))
+			}
+		}
+	}
+
+	return errors
+}
+
+func validateHeaderRefs(doc *Doc, responses map[string]Response) []error {
+	errors := []error{}
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		names := make([]string, 0, len(responses[status].Headers))
+		for name := range responses[status].Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ref := strings.TrimPrefix(responses[status].Headers[name].Ref, "#/components/headers/")
+			if _, ok := doc.Components.Headers[ref]; ref != "" && !ok {
+				errors = append(errors, fmt.Errorf("unknown header component '%s'", ref))
+			}
+		}
+	}
+
+	return errors
+}
+
+func validateSecurityRequirements(doc *Doc, requirements SecurityRequirements) []error {
+	errors := []error{}
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			scheme, ok := doc.Components.SecuritySchemes[name]
+			if !ok {
+				errors = append(errors, fmt.Errorf("unknown security scheme '%s'", name))
+				continue
+			}
+
+			if scheme.Type == "openIdConnect" {
+				continue
+			}
+			if scheme.Type != "oauth2" && len(requirement[name]) > 0 {
+				errors = append(errors, fmt.Errorf("security scheme '%s' does not support scopes", name))
+				continue
+			}
+
+			for _, scope := range requirement[name] {
+				if !scheme.Flows.hasScope(scope) {
+					errors = append(errors, fmt.Errorf("unknown scope '%s' for security scheme '%s'", scope, name))
+				}
+			}
+		}
+	}
+
+	return errors
+}